@@ -1,42 +1,90 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 
 	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+	"github.com/cheshire137/gogoamazonwish/pkg/render"
 )
 
+// snapshotWriter writes a wishlist snapshot in some format.
+type snapshotWriter func(io.Writer, *amazon.Snapshot) error
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: Amazon_wishlist_URL [proxy URL]...")
-		fmt.Println("\tspecify optional proxy URLs at the end")
-		os.Exit(1)
+	format := flag.String("format", "", "export the wishlist as csv, tsv, xlsx, json, markdown, html, atom, or rss instead of printing it")
+	columnNames := flag.String("columns", "", "comma-separated columns to export, e.g., name,price,link")
+	outputPath := flag.String("output", "", "file to write the export to; defaults to standard output")
+	language := flag.String("language", "", "language to format exported numbers in, e.g., de-DE")
+	templatePath := flag.String("template", "", "text/template or html/template file to render the wishlist with")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] Amazon_wishlist_URL [proxy URL]...\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "\tspecify optional proxy URLs at the end")
+		flag.PrintDefaults()
 	}
-	url := os.Args[1]
-	fmt.Printf("Got URL: %s\n", url)
+	flag.Parse()
 
-	proxyURLs := []string{}
-	if len(os.Args) > 3 {
-		for i := 3; i < len(os.Args); i++ {
-			proxyURLs = append(proxyURLs, os.Args[i])
-		}
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
 	}
+	url := flag.Arg(0)
+	proxyURLs := flag.Args()[1:]
 
-	wishlist, err := amazon.NewWishlist(url)
+	var wishlist *amazon.Wishlist
+	var err error
+	if amazon.IsShortLink(url) {
+		wishlist, err = amazon.NewWishlistFromShortLink(nil, url)
+	} else {
+		wishlist, err = amazon.NewWishlist(url)
+	}
 	if err != nil {
 		log.Fatalln(err)
 	}
 
+	if err := wishlist.SetProxyURLs(proxyURLs...); err != nil {
+		log.Fatalln(err)
+	}
+
+	if *format != "" || *templatePath != "" {
+		options := amazon.SpreadsheetOptions{Language: *language}
+		if *columnNames != "" {
+			if options.Columns, err = amazon.ParseColumns(*columnNames); err != nil {
+				log.Fatalln(err)
+			}
+		}
+		write, err := writerFor(*format, *templatePath, options)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := export(wishlist, write, *outputPath); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	fmt.Printf("Got URL: %s\n", url)
 	wishlist.DebugMode = true
-	wishlist.SetProxyURLs(proxyURLs...)
 
-	name, err := wishlist.Name()
+	metadata, err := wishlist.Metadata()
 	if err != nil {
 		log.Fatalln(err)
 	}
-	fmt.Println(name)
+	fmt.Println(metadata.Name)
+	if metadata.OwnerName != "" {
+		fmt.Printf("Owner: %s\n", metadata.OwnerName)
+	}
+	if metadata.Description != "" {
+		fmt.Println(metadata.Description)
+	}
+	fmt.Printf("Privacy: %s\n", metadata.Privacy)
+	if metadata.HasShippingAddress {
+		fmt.Printf("Ships to: %s\n", metadata.ShipTo)
+	}
 
 	printURL, err := wishlist.PrintURL()
 	if err != nil {
@@ -54,4 +102,77 @@ func main() {
 		fmt.Printf("%d) %s\n\n", number, item)
 		number++
 	}
+
+	if pool := wishlist.ProxyPool(); pool != nil {
+		fmt.Printf("Proxy stats:\n%s\n", pool)
+	}
+}
+
+// writerFor returns the writer for the given format, or for the given
+// template file if there is one.
+func writerFor(format, templatePath string, options amazon.SpreadsheetOptions) (snapshotWriter, error) {
+	if templatePath != "" {
+		tmpl, err := render.ParseFiles(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		return func(w io.Writer, snapshot *amazon.Snapshot) error {
+			return tmpl.Execute(w, snapshot)
+		}, nil
+	}
+
+	switch format {
+	case "csv":
+		return withOptions(amazon.WriteCSV, options), nil
+	case "tsv":
+		return withOptions(amazon.WriteTSV, options), nil
+	case "xlsx":
+		return withOptions(amazon.WriteXLSX, options), nil
+	case "json":
+		return writeJSON, nil
+	case "markdown":
+		return render.Markdown, nil
+	case "html":
+		return render.HTML, nil
+	case "atom":
+		return render.Atom, nil
+	case "rss":
+		return render.RSS, nil
+	}
+	return nil, fmt.Errorf("Unknown format '%s', expected csv, tsv, xlsx, json, markdown, html, atom, or rss", format)
+}
+
+// export writes a snapshot of the wishlist to the given file, or to standard
+// output if no path is given.
+func export(wishlist *amazon.Wishlist, write snapshotWriter, outputPath string) error {
+	snapshot, err := wishlist.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	if outputPath == "" {
+		return write(os.Stdout, snapshot)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := write(file, snapshot); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func withOptions(write func(io.Writer, *amazon.Snapshot, amazon.SpreadsheetOptions) error, options amazon.SpreadsheetOptions) snapshotWriter {
+	return func(w io.Writer, snapshot *amazon.Snapshot) error {
+		return write(w, snapshot, options)
+	}
+}
+
+func writeJSON(w io.Writer, snapshot *amazon.Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
 }
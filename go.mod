@@ -3,7 +3,7 @@ module github.com/cheshire137/gogoamazonwish
 go 1.13
 
 require (
-	github.com/PuerkitoBio/goquery v1.5.0 // indirect
+	github.com/PuerkitoBio/goquery v1.5.0
 	github.com/andybalholm/cascadia v1.1.0 // indirect
 	github.com/antchfx/htmlquery v1.2.1 // indirect
 	github.com/antchfx/xmlquery v1.2.2 // indirect
@@ -0,0 +1,51 @@
+// Package amazontest provides snapshots of the wishlist recorded in this
+// repository's tests, for testing packages that work with snapshots without
+// loading anything from Amazon.
+package amazontest
+
+import (
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+const (
+	// WishlistID is the ID of the recorded wishlist.
+	WishlistID = "3I6EQPZ8OB1DT"
+
+	// WishlistName is the name of the recorded wishlist.
+	WishlistName = "NHA Wish List"
+
+	// ItemID is the ID of the item on the recorded wishlist.
+	ItemID = "I2G6UJO0FYWV8J"
+
+	// ItemName is the name of the item on the recorded wishlist.
+	ItemName = "Purina Tidy Cats Non-Clumping Cat Litter"
+
+	// ASIN is the product ID of the item on the recorded wishlist.
+	ASIN = "B0018CLTKE"
+)
+
+// Start is when the first snapshot in a test is taken.
+var Start = time.Date(2020, time.January, 4, 18, 30, 0, 0, time.UTC)
+
+// NewItem returns an item for the recorded wishlist's product, with the given
+// item ID and price.
+func NewItem(id string, price string) *amazon.Item {
+	item := amazon.NewItem(id, ItemName,
+		"https://www.amazon.com/dp/"+ASIN+"/?coliid="+id)
+	item.Price = price
+	return item
+}
+
+// NewSnapshot returns a snapshot of the recorded wishlist with the given items,
+// taken the given number of days after Start.
+func NewSnapshot(day int, items ...*amazon.Item) *amazon.Snapshot {
+	return &amazon.Snapshot{
+		ID:       WishlistID,
+		Kind:     amazon.ListKindWishlist,
+		Metadata: amazon.Metadata{Name: WishlistName},
+		Items:    items,
+		TakenAt:  Start.AddDate(0, 0, day),
+	}
+}
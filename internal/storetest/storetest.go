@@ -0,0 +1,91 @@
+// Package storetest checks that implementations of store.Store behave the
+// same, for the store package's tests and those of backends kept in modules of
+// their own.
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/internal/amazontest"
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+	"github.com/cheshire137/gogoamazonwish/pkg/store"
+	"github.com/stretchr/testify/require"
+)
+
+func newSnapshot(day int, price string, itemIDs ...string) *amazon.Snapshot {
+	items := []*amazon.Item{}
+	for _, id := range itemIDs {
+		items = append(items, amazontest.NewItem(id, price))
+	}
+	return amazontest.NewSnapshot(day, items...)
+}
+
+// Run runs the tests every Store implementation must pass against s, which
+// must be empty.
+func Run(t *testing.T, s store.Store) {
+	_, err := s.Latest(amazontest.WishlistID)
+	require.Equal(t, store.ErrNotFound, err)
+
+	first := newSnapshot(0, "$10.00", "A", "B")
+	second := newSnapshot(1, "$12.50", "B", "C")
+	third := newSnapshot(2, "$9.99", "A", "B")
+	for _, snapshot := range []*amazon.Snapshot{second, first, third} {
+		require.NoError(t, s.Save(snapshot))
+	}
+
+	latest, err := s.Latest(amazontest.WishlistID)
+	require.NoError(t, err)
+	require.True(t, third.TakenAt.Equal(latest.TakenAt))
+	require.Equal(t, amazontest.WishlistName, latest.Metadata.Name)
+	require.Len(t, latest.Items, 2)
+	require.Equal(t, "A", latest.Items[0].ID)
+
+	past, err := s.At(amazontest.WishlistID, amazontest.Start.Add(36*time.Hour))
+	require.NoError(t, err)
+	require.True(t, second.TakenAt.Equal(past.TakenAt))
+	require.NotNil(t, past.Item("C"))
+
+	_, err = s.At(amazontest.WishlistID, amazontest.Start.Add(-time.Second))
+	require.Equal(t, store.ErrNotFound, err)
+
+	times, err := s.Snapshots(amazontest.WishlistID)
+	require.NoError(t, err)
+	require.Len(t, times, 3)
+	require.True(t, first.TakenAt.Equal(times[0]))
+	require.True(t, third.TakenAt.Equal(times[2]))
+
+	history, err := s.ItemHistory(amazontest.WishlistID, "A")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.True(t, first.TakenAt.Equal(history[0].TakenAt))
+	require.Equal(t, "$10.00", history[0].Item.Price)
+	require.Equal(t, "$9.99", history[1].Item.Price)
+
+	// Saving a snapshot taken at the same time replaces it.
+	require.NoError(t, s.Save(newSnapshot(2, "$8.00", "A")))
+	latest, err = s.Latest(amazontest.WishlistID)
+	require.NoError(t, err)
+	require.Len(t, latest.Items, 1)
+	times, err = s.Snapshots(amazontest.WishlistID)
+	require.NoError(t, err)
+	require.Len(t, times, 3)
+
+	deleted, err := s.Prune(amazontest.WishlistID, store.PrunePolicy{KeepLast: 2})
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	history, err = s.ItemHistory(amazontest.WishlistID, "A")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "$8.00", history[0].Item.Price)
+
+	times, err = s.Snapshots("ZZZ")
+	require.NoError(t, err)
+	require.Empty(t, times)
+
+	_, err = s.Latest("../etc")
+	require.Error(t, err)
+	undated := newSnapshot(0, "$1.00")
+	undated.TakenAt = time.Time{}
+	require.Error(t, s.Save(undated))
+}
@@ -0,0 +1,129 @@
+package amazon
+
+import (
+	"strings"
+)
+
+// Availability describes whether a product on a wishlist can currently be
+// bought on Amazon.
+type Availability int
+
+const (
+	// AvailabilityUnknown means Amazon did not say whether the product is
+	// available, or said so in a way we don't recognize.
+	AvailabilityUnknown Availability = iota
+
+	// AvailabilityInStock means Amazon itself has the product in stock.
+	AvailabilityInStock
+
+	// AvailabilityLimited means only a few of the product are left in stock.
+	AvailabilityLimited
+
+	// AvailabilityOtherSellers means the product can only be bought from
+	// third-party sellers.
+	AvailabilityOtherSellers
+
+	// AvailabilityUnavailable means the product cannot currently be bought.
+	AvailabilityUnavailable
+)
+
+// AvailabilityPhrase maps a phrase in the availability note Amazon shows for
+// an item, e.g., "left in stock", to what it says about the item.
+type AvailabilityPhrase struct {
+	Phrase       string
+	Availability Availability
+}
+
+// The phrases for each language are checked in order: "currently
+// unavailable" must win over "available" and "only 3 left in stock" must win
+// over "in stock".
+var (
+	englishAvailabilityPhrases = []AvailabilityPhrase{
+		{"currently unavailable", AvailabilityUnavailable},
+		{"no longer available", AvailabilityUnavailable},
+		{"out of stock", AvailabilityUnavailable},
+		{"available from these sellers", AvailabilityOtherSellers},
+		{"see all buying options", AvailabilityOtherSellers},
+		{"left in stock", AvailabilityLimited},
+		{"in stock", AvailabilityInStock},
+	}
+	frenchAvailabilityPhrases = []AvailabilityPhrase{
+		{"actuellement indisponible", AvailabilityUnavailable},
+		{"rupture de stock", AvailabilityUnavailable},
+		{"disponible auprès de ces vendeurs", AvailabilityOtherSellers},
+		{"voir toutes les options d'achat", AvailabilityOtherSellers},
+		{"il ne reste plus que", AvailabilityLimited},
+		{"en stock", AvailabilityInStock},
+	}
+	spanishAvailabilityPhrases = []AvailabilityPhrase{
+		{"no disponible", AvailabilityUnavailable},
+		{"disponible a través de estos vendedores", AvailabilityOtherSellers},
+		{"ver todas las opciones de compra", AvailabilityOtherSellers},
+		{"solo quedan", AvailabilityLimited},
+		{"sólo quedan", AvailabilityLimited},
+		{"en stock", AvailabilityInStock},
+		{"en existencia", AvailabilityInStock},
+	}
+	dutchAvailabilityPhrases = []AvailabilityPhrase{
+		{"momenteel niet verkrijgbaar", AvailabilityUnavailable},
+		{"niet op voorraad", AvailabilityUnavailable},
+		{"verkrijgbaar bij deze verkopers", AvailabilityOtherSellers},
+		{"bekijk alle koopopties", AvailabilityOtherSellers},
+		{"nog maar", AvailabilityLimited},
+		{"op voorraad", AvailabilityInStock},
+	}
+)
+
+// String returns a short description of the availability.
+func (a Availability) String() string {
+	switch a {
+	case AvailabilityInStock:
+		return "in stock"
+	case AvailabilityLimited:
+		return "limited stock"
+	case AvailabilityOtherSellers:
+		return "from other sellers"
+	case AvailabilityUnavailable:
+		return "unavailable"
+	}
+	return "unknown"
+}
+
+// IsAvailable returns true if the product can be bought, whether from Amazon
+// or from another seller.
+func (a Availability) IsAvailable() bool {
+	return a == AvailabilityInStock || a == AvailabilityLimited ||
+		a == AvailabilityOtherSellers
+}
+
+// parseAvailability determines an item's availability from the note the
+// marketplace shows for it, e.g., "Nur noch 3 auf Lager".
+func (m *Marketplace) parseAvailability(text string) Availability {
+	text = strings.ToLower(text)
+	for _, p := range m.AvailabilityPhrases {
+		if strings.Contains(text, strings.ToLower(p.Phrase)) {
+			return p.Availability
+		}
+	}
+	return AvailabilityUnknown
+}
+
+// parseByline splits a byline like "by Jane Doe (Paperback)" into the credited
+// name and the format.
+func parseByline(text string) (string, string) {
+	byline := strings.Join(strings.Fields(text), " ")
+	format := ""
+
+	if strings.HasSuffix(byline, ")") {
+		if start := strings.LastIndex(byline, "("); start > -1 {
+			format = strings.TrimSpace(byline[start+1 : len(byline)-1])
+			byline = strings.TrimSpace(byline[:start])
+		}
+	}
+
+	if strings.HasPrefix(strings.ToLower(byline), bylinePrefix) {
+		byline = strings.TrimSpace(byline[len(bylinePrefix):])
+	}
+
+	return byline, format
+}
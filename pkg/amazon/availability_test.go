@@ -0,0 +1,90 @@
+package amazon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAvailability(t *testing.T) {
+	tests := []struct {
+		host     string
+		text     string
+		expected Availability
+	}{
+		{"www.amazon.com", "In Stock.", AvailabilityInStock},
+		{"www.amazon.com", "Only 3 left in stock - order soon.", AvailabilityLimited},
+		{"www.amazon.com", "Currently unavailable.", AvailabilityUnavailable},
+		{"www.amazon.com", "Available from these sellers.", AvailabilityOtherSellers},
+		{"www.amazon.com", "Usually ships within 2 to 3 weeks.", AvailabilityUnknown},
+		{"www.amazon.com", "", AvailabilityUnknown},
+		{"www.amazon.de", "Auf Lager.", AvailabilityInStock},
+		{"www.amazon.de", "Nur noch 3 auf Lager (mehr ist unterwegs).", AvailabilityLimited},
+		{"www.amazon.de", "Derzeit nicht verfügbar.", AvailabilityUnavailable},
+		{"www.amazon.fr", "Il ne reste plus que 2 exemplaire(s) en stock.", AvailabilityLimited},
+		{"www.amazon.ca", "Actuellement indisponible.", AvailabilityUnavailable},
+		{"www.amazon.ca", "In Stock.", AvailabilityInStock},
+		{"www.amazon.co.jp", "残り3点 ご注文はお早めに", AvailabilityLimited},
+		{"www.amazon.nl", "Op voorraad.", AvailabilityInStock},
+		{"www.amazon.de", "In Stock.", AvailabilityUnknown},
+	}
+
+	for _, test := range tests {
+		m, ok := MarketplaceForHost(test.host)
+		require.True(t, ok, test.host)
+		require.Equal(t, test.expected, m.parseAvailability(test.text), test.text)
+	}
+}
+
+func TestParseByline(t *testing.T) {
+	tests := []struct {
+		text           string
+		expectedByline string
+		expectedFormat string
+	}{
+		{"by Jane Doe (Paperback)", "Jane Doe", "Paperback"},
+		{"  by\n  Purina  ", "Purina", ""},
+		{"by Jane Doe, John Smith (Audible Audiobook)", "Jane Doe, John Smith", "Audible Audiobook"},
+		{"(Kindle Edition)", "", "Kindle Edition"},
+		{"", "", ""},
+	}
+
+	for _, test := range tests {
+		byline, format := parseByline(test.text)
+		require.Equal(t, test.expectedByline, byline, test.text)
+		require.Equal(t, test.expectedFormat, format, test.text)
+	}
+}
+
+func TestOnBylineAndAvailabilitySpans(t *testing.T) {
+	wishlist, err := NewWishlistFromID("3I6EQPZ8OB1DT")
+	require.NoError(t, err)
+	wishlist.addItem("I2G6UJO0FYWV8J", "Cat Litter", "https://www.amazon.com/dp/B0018CLTKE")
+
+	wishlist.onBylineSpan("I2G6UJO0FYWV8J", newTestHTMLElement(t,
+		`<span id="item-byline-I2G6UJO0FYWV8J" class="a-size-base">by Purina Tidy Cats (Misc.)</span>`))
+	wishlist.onAvailabilitySpan("I2G6UJO0FYWV8J", newTestHTMLElement(t,
+		`<span id="availability-msg_I2G6UJO0FYWV8J" class="a-size-small a-color-price itemAvailMessage">
+			Only 3 left in stock - order soon.
+		</span>`))
+
+	item := wishlist.items["I2G6UJO0FYWV8J"]
+	require.Equal(t, "Purina Tidy Cats", item.Byline)
+	require.Equal(t, "Misc.", item.Format)
+	require.Equal(t, "Only 3 left in stock - order soon", item.RawAvailability)
+	require.Equal(t, AvailabilityLimited, item.Availability)
+}
+
+// newTestHTMLElement returns the first element in the given markup, as colly
+// would pass it to a callback.
+func newTestHTMLElement(t *testing.T, markup string) *colly.HTMLElement {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(markup))
+	require.NoError(t, err)
+	selection := doc.Find("body > *").First()
+	require.Equal(t, 1, selection.Length())
+	return colly.NewHTMLElementFromSelectionNode(&colly.Response{}, selection,
+		selection.Nodes[0], 0)
+}
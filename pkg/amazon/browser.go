@@ -0,0 +1,125 @@
+package amazon
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	chromeAccept  = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
+	firefoxAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8"
+	safariAccept  = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+)
+
+var (
+	// browserProfiles are current desktop browsers that Amazon serves the
+	// full site to.
+	browserProfiles = []BrowserProfile{
+		{
+			Name:      "Chrome on Windows",
+			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Accept:    chromeAccept,
+			Headers:   map[string]string{"Upgrade-Insecure-Requests": "1"},
+		},
+		{
+			Name:      "Chrome on macOS",
+			UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Accept:    chromeAccept,
+			Headers:   map[string]string{"Upgrade-Insecure-Requests": "1"},
+		},
+		{
+			Name:      "Edge on Windows",
+			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			Accept:    chromeAccept,
+			Headers:   map[string]string{"Upgrade-Insecure-Requests": "1"},
+		},
+		{
+			Name:      "Firefox on Windows",
+			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			Accept:    firefoxAccept,
+			Headers:   map[string]string{"Upgrade-Insecure-Requests": "1", "DNT": "1"},
+		},
+		{
+			Name:      "Safari on macOS",
+			UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			Accept:    safariAccept,
+		},
+	}
+
+	browserRand      = rand.New(rand.NewSource(time.Now().UnixNano()))
+	browserRandMutex sync.Mutex
+)
+
+// BrowserProfile is a set of request headers that together look like one
+// real browser, so every request of a crawl is consistent.
+type BrowserProfile struct {
+	// Name describes the browser, e.g., "Firefox on Windows".
+	Name string
+
+	// UserAgent is the User-Agent header the browser sends.
+	UserAgent string
+
+	// Accept is the Accept header the browser sends for web pages.
+	Accept string
+
+	// Headers are any other headers the browser sends with every request,
+	// such as "Upgrade-Insecure-Requests".
+	Headers map[string]string
+}
+
+// BrowserProfiles returns the built-in browser profiles, one of which is
+// picked at random for each wishlist unless Wishlist.Browser is set.
+func BrowserProfiles() []BrowserProfile {
+	profiles := make([]BrowserProfile, len(browserProfiles))
+	for i, profile := range browserProfiles {
+		profiles[i] = *profile.copy()
+	}
+	return profiles
+}
+
+// randomBrowserProfile returns one of the built-in browser profiles.
+func randomBrowserProfile() *BrowserProfile {
+	browserRandMutex.Lock()
+	index := browserRand.Intn(len(browserProfiles))
+	browserRandMutex.Unlock()
+
+	return browserProfiles[index].copy()
+}
+
+// copy returns a copy of the profile with a headers map of its own, so
+// changing the copy's headers leaves the built-in profiles alone.
+func (b BrowserProfile) copy() *BrowserProfile {
+	if b.Headers != nil {
+		headers := make(map[string]string, len(b.Headers))
+		for name, value := range b.Headers {
+			headers[name] = value
+		}
+		b.Headers = headers
+	}
+	return &b
+}
+
+// String returns the name of the browser.
+func (b *BrowserProfile) String() string {
+	return b.Name
+}
+
+// acceptLanguage returns an Accept-Language header preferring the given
+// BCP 47 language, e.g., "de-DE,de;q=0.9,en;q=0.8" for "de-DE".
+func acceptLanguage(language string) string {
+	if language == "" {
+		language = "en-US"
+	}
+
+	tags := []string{language}
+	base := strings.SplitN(language, "-", 2)[0]
+	if base != language {
+		tags = append(tags, base+";q=0.9")
+	}
+	if base != "en" {
+		tags = append(tags, "en;q=0.8")
+	}
+	return strings.Join(tags, ",")
+}
@@ -0,0 +1,130 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptLanguage(t *testing.T) {
+	require.Equal(t, "en-US,en;q=0.9", acceptLanguage("en-US"))
+	require.Equal(t, "en-US,en;q=0.9", acceptLanguage(""))
+	require.Equal(t, "de-DE,de;q=0.9,en;q=0.8", acceptLanguage("de-DE"))
+	require.Equal(t, "ja,en;q=0.8", acceptLanguage("ja"))
+}
+
+func TestBrowserProfiles(t *testing.T) {
+	profiles := BrowserProfiles()
+	require.NotEmpty(t, profiles)
+	for _, profile := range profiles {
+		require.NotEmpty(t, profile.Name)
+		require.Contains(t, profile.UserAgent, "Mozilla/5.0", profile.Name)
+		require.NotEmpty(t, profile.Accept, profile.Name)
+	}
+
+	profiles[0].Name = "changed"
+	require.NotEqual(t, "changed", BrowserProfiles()[0].Name)
+
+	// Built-in profiles, whether listed or picked for a wishlist, are
+	// copies whose headers can be changed without changing the originals.
+	profiles[0].Headers["X-Listed"] = "1"
+	browserProfiles[0].copy().Headers["X-Picked"] = "1"
+	require.NotContains(t, browserProfiles[0].Headers, "X-Listed")
+	require.NotContains(t, browserProfiles[0].Headers, "X-Picked")
+}
+
+func TestItemsSendsConsistentBrowserHeaders(t *testing.T) {
+	id := "123abc"
+	var mutex sync.Mutex
+	requests := []http.Header{}
+	ts := newPagedTestServer(t, id, func(r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests = append(requests, r.Header)
+	})
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.NotNil(t, wishlist.Browser, "should pick a browser to keep using")
+
+	_, err = wishlist.Name()
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3)
+	for _, header := range requests {
+		require.Equal(t, wishlist.Browser.UserAgent, header.Get("User-Agent"))
+		require.Equal(t, wishlist.Browser.Accept, header.Get("Accept"))
+		require.Equal(t, "en-US,en;q=0.9", header.Get("Accept-Language"))
+	}
+}
+
+func TestItemsWithPinnedUserAgent(t *testing.T) {
+	id := "123abc"
+	var mutex sync.Mutex
+	userAgents := []string{}
+	ts := newPagedTestServer(t, id, func(r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+	})
+	defer ts.Close()
+
+	browser := BrowserProfiles()[0]
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+	wishlist.Browser = &browser
+	wishlist.UserAgent = "MyWishlistApp/1.0"
+
+	_, err = wishlist.Items()
+	require.NoError(t, err)
+	require.Equal(t, []string{"MyWishlistApp/1.0", "MyWishlistApp/1.0"}, userAgents)
+	require.Equal(t, &browser, wishlist.Browser)
+}
+
+const pagedWishlistHTML = `<html>
+	<body>
+		<span id="profile-list-name">Paged List</span>
+		<ul>
+			<li data-itemid="IPAGE1"><a id="itemName_IPAGE1" href="/dp/B0018CLTKE" title="Cat Litter">Cat Litter</a></li>
+		</ul>
+		<a class="wl-see-more" href="?page=2">See more</a>
+	</body>
+</html>`
+
+const pagedWishlistPage2HTML = `<html>
+	<body>
+		<ul>
+			<li data-itemid="IPAGE2"><a id="itemName_IPAGE2" href="/dp/0134190440" title="The Go Programming Language">The Go Programming Language</a></li>
+		</ul>
+	</body>
+</html>`
+
+// newPagedTestServer serves a wishlist split over two pages, calling
+// onRequest with each request for it.
+func newPagedTestServer(t *testing.T, wishlistID string, onRequest func(*http.Request)) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/hz/wishlist/ls/"+wishlistID, func(w http.ResponseWriter, r *http.Request) {
+		onRequest(r)
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(pagedWishlistPage2HTML))
+		} else {
+			w.Write([]byte(pagedWishlistHTML))
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
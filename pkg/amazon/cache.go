@@ -0,0 +1,116 @@
+package amazon
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+)
+
+// cacheTransport serves responses from files in a directory, saving the ones
+// it fetches there. Only successful pages are saved: errors, redirects, and
+// robot checks are always fetched again. Each hop of a redirect is a request
+// of its own, so a response is always saved under the URL that returned it,
+// never under the URL that redirected to it. A response that can't be saved
+// is still returned, since the cache only saves fetching it again.
+type cacheTransport struct {
+	dir       string
+	transport http.RoundTripper
+	debug     bool
+}
+
+// RoundTrip returns the cached response for the request if there is one, and
+// otherwise sends the request on, caching the response if it's worth keeping.
+func (t *cacheTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet {
+		return t.transport.RoundTrip(r)
+	}
+
+	filename := t.filename(r)
+	if data, err := ioutil.ReadFile(filename); err == nil {
+		response, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), r)
+		if err == nil {
+			return response, nil
+		}
+	}
+
+	response, err := t.transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return response, nil
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if isRobotPage(body, marketplaceForHost(r.URL.Hostname())) {
+		return response, nil
+	}
+
+	if err := t.save(filename, response, body); err != nil && t.debug {
+		fmt.Println("Could not cache response from", r.URL, "-", err)
+	}
+
+	return response, nil
+}
+
+// filename returns the path of the file the response to the given request is
+// cached in.
+func (t *cacheTransport) filename(r *http.Request) string {
+	sum := sha256.Sum256([]byte(r.URL.String()))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:])+".http")
+}
+
+// save writes the response, whose body has already been read, to the given
+// file, via a temporary file so a partly written response is never read back.
+// Cookies Amazon sets, such as a session after a captcha is solved, are left
+// out so they don't end up on disk.
+func (t *cacheTransport) save(filename string, response *http.Response, body []byte) error {
+	saved := *response
+	saved.Header = http.Header{}
+	for key, values := range response.Header {
+		if key != "Set-Cookie" {
+			saved.Header[key] = values
+		}
+	}
+	saved.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	data, err := httputil.DumpResponse(&saved, true)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(t.dir, 0750); err != nil {
+		return err
+	}
+
+	// Name the temporary file uniquely, since requests for the same URL can
+	// be cached at the same time.
+	file, err := ioutil.TempFile(t.dir, ".response-")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), filename)
+}
@@ -0,0 +1,111 @@
+package amazon
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheTransportSkipsRobotPageAfterRedirect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	redirects := 0
+	pages := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hz/wishlist/ls/123abc", func(w http.ResponseWriter, r *http.Request) {
+		redirects++
+		http.Redirect(w, r, "/hz/wishlist/ls/123abc/final", http.StatusFound)
+	})
+	mux.HandleFunc("/hz/wishlist/ls/123abc/final", func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		w.Header().Set("Content-Type", "text/html")
+		if pages == 1 {
+			w.Write([]byte(`<form method="get" action="/errors/validateCaptcha"></form>`))
+			return
+		}
+		w.Write([]byte(wishlistHTML))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client := &http.Client{Transport: &cacheTransport{dir: dir, transport: http.DefaultTransport}}
+	get := func() string {
+		resp, err := client.Get(ts.URL + "/hz/wishlist/ls/123abc")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	require.Contains(t, get(), "validateCaptcha")
+	require.Contains(t, get(), "NHA Wish List", "should not serve the cached robot page")
+	require.Contains(t, get(), "NHA Wish List")
+
+	require.Equal(t, 3, redirects, "should not cache redirects")
+	require.Equal(t, 2, pages, "should serve the wishlist from the cache")
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestCacheTransportLeavesOutCookies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session-id", Value: "123-4567890"})
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(wishlistHTML))
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: &cacheTransport{dir: dir, transport: http.DefaultTransport}}
+	resp, err := client.Get(ts.URL + "/hz/wishlist/ls/123abc")
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Contains(t, string(body), "NHA Wish List")
+	require.Contains(t, resp.Header.Get("Set-Cookie"), "session-id", "should pass cookies on")
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "should not leave temporary files behind")
+	data, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "123-4567890")
+	require.Contains(t, string(data), "NHA Wish List")
+}
+
+func TestCacheTransportReturnsResponseWhenSaveFails(t *testing.T) {
+	file, err := ioutil.TempFile("", "cache")
+	require.NoError(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(wishlistHTML))
+	}))
+	defer ts.Close()
+
+	// A directory can't be made inside a file, so saving the response fails.
+	dir := filepath.Join(file.Name(), "cache")
+	client := &http.Client{Transport: &cacheTransport{dir: dir, transport: http.DefaultTransport}}
+	resp, err := client.Get(ts.URL + "/hz/wishlist/ls/123abc")
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Contains(t, string(body), "NHA Wish List")
+}
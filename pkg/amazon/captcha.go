@@ -0,0 +1,187 @@
+package amazon
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+)
+
+const (
+	// maxCaptchaAttempts is how many captchas will be solved for one page
+	// before giving up.
+	maxCaptchaAttempts = 3
+
+	captchaAttemptsKey  = "captchaAttempts"
+	captchaFormSelector = "form[action*='validateCaptcha']"
+)
+
+// CaptchaChallenge is the captcha form Amazon shows instead of a page when it
+// thinks you're a robot.
+type CaptchaChallenge struct {
+	// ImageURL is the URL of the image with the characters to type.
+	ImageURL string
+
+	// ActionURL is the URL the answer is submitted to.
+	ActionURL string
+
+	// Method is the HTTP method the form is submitted with, usually "GET".
+	Method string
+
+	// Fields are the hidden fields of the form, submitted with the answer.
+	Fields url.Values
+
+	// AnswerField is the name of the form field the answer goes in.
+	AnswerField string
+
+	// PageURL is the URL of the page Amazon showed the captcha instead of.
+	PageURL string
+}
+
+// CaptchaSolver is given the captcha Amazon showed instead of a page and
+// returns the characters in its image. Returning an empty answer or an error
+// gives up on loading the page. A solver may be called from several goroutines
+// at once.
+type CaptchaSolver func(challenge *CaptchaChallenge) (string, error)
+
+// parseCaptchaChallenge reads the captcha form from the given robot page.
+func parseCaptchaChallenge(body []byte, pageURL *url.URL) (*CaptchaChallenge, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	form := doc.Find(captchaFormSelector).First()
+	if form.Length() < 1 {
+		return nil, errors.New("No captcha form found on the page")
+	}
+
+	action, err := pageURL.Parse(form.AttrOr("action", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &CaptchaChallenge{
+		ActionURL: action.String(),
+		Method:    strings.ToUpper(form.AttrOr("method", "GET")),
+		Fields:    url.Values{},
+		PageURL:   pageURL.String(),
+	}
+
+	form.Find("input").Each(func(index int, input *goquery.Selection) {
+		name := input.AttrOr("name", "")
+		if name == "" {
+			return
+		}
+		if strings.EqualFold(input.AttrOr("type", "text"), "hidden") {
+			challenge.Fields.Add(name, input.AttrOr("value", ""))
+		} else if challenge.AnswerField == "" {
+			challenge.AnswerField = name
+		}
+	})
+	if challenge.AnswerField == "" {
+		return nil, errors.New("No field for the captcha answer found in the form")
+	}
+
+	if src, ok := form.Find("img").First().Attr("src"); ok {
+		imageURL, err := pageURL.Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		challenge.ImageURL = imageURL.String()
+	}
+	if challenge.ImageURL == "" {
+		return nil, errors.New("No captcha image found in the form")
+	}
+
+	return challenge, nil
+}
+
+// values returns the form fields to submit with the given answer.
+func (challenge *CaptchaChallenge) values(answer string) url.Values {
+	values := url.Values{}
+	for name, fieldValues := range challenge.Fields {
+		values[name] = append([]string{}, fieldValues...)
+	}
+	values.Set(challenge.AnswerField, answer)
+	return values
+}
+
+// onCaptcha has the wishlist's CaptchaSolver solve the captcha on the given
+// robot page, submits the answer, and then requests the page again. Returns
+// ErrRobot if the captcha can't be solved.
+func (w *Wishlist) onCaptcha(c *colly.Collector, r *colly.Response) error {
+	if w.CaptchaSolver == nil {
+		return ErrRobot
+	}
+
+	attempts, _ := r.Ctx.GetAny(captchaAttemptsKey).(int)
+	if attempts >= maxCaptchaAttempts {
+		return ErrRobot
+	}
+	r.Ctx.Put(captchaAttemptsKey, attempts+1)
+
+	challenge, err := parseCaptchaChallenge(r.Body, r.Request.URL)
+	if err != nil {
+		return ErrRobot
+	}
+
+	answer, err := w.CaptchaSolver(challenge)
+	if err != nil {
+		return err
+	}
+	if answer == "" {
+		return ErrRobot
+	}
+
+	if err := w.submitCaptcha(c, challenge, answer); err != nil {
+		return err
+	}
+
+	return r.Request.Retry()
+}
+
+// submitCaptcha sends the answer to the captcha, waiting for Amazon's
+// response. The cookies Amazon sets are kept in the collector's cookie jar,
+// which is shared with the clone used here.
+func (w *Wishlist) submitCaptcha(c *colly.Collector, challenge *CaptchaChallenge, answer string) error {
+	submitter := c.Clone()
+	submitter.Async = false
+	submitter.AllowURLRevisit = true
+	submitter.OnRequest(w.onRequest)
+
+	var submitErr error
+	submitter.OnError(func(r *colly.Response, err error) {
+		submitErr = err
+	})
+
+	values := challenge.values(answer)
+	var err error
+	if challenge.Method == http.MethodPost {
+		data := map[string]string{}
+		for name := range values {
+			data[name] = values.Get(name)
+		}
+		err = submitter.Post(challenge.ActionURL, data)
+	} else {
+		actionURL, parseErr := url.Parse(challenge.ActionURL)
+		if parseErr != nil {
+			return parseErr
+		}
+		query := actionURL.Query()
+		for name, fieldValues := range values {
+			query[name] = fieldValues
+		}
+		actionURL.RawQuery = query.Encode()
+		err = submitter.Visit(actionURL.String())
+	}
+	if err != nil {
+		return err
+	}
+
+	return submitErr
+}
@@ -0,0 +1,152 @@
+package amazon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCaptchaChallenge(t *testing.T) {
+	pageURL, err := url.Parse("https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT")
+	require.NoError(t, err)
+
+	challenge, err := parseCaptchaChallenge([]byte(captchaHTML), pageURL)
+	require.NoError(t, err)
+	require.Equal(t, "https://www.amazon.com/captcha/Captcha_abcdef.jpg", challenge.ImageURL)
+	require.Equal(t, "https://www.amazon.com/errors/validateCaptcha", challenge.ActionURL)
+	require.Equal(t, "GET", challenge.Method)
+	require.Equal(t, "field-keywords", challenge.AnswerField)
+	require.Equal(t, "a1b2c3", challenge.Fields.Get("amzn"))
+	require.Equal(t, "/hz/wishlist/ls/123abc", challenge.Fields.Get("amzn-r"))
+	require.Equal(t, pageURL.String(), challenge.PageURL)
+
+	values := challenge.values("XKCDNR")
+	require.Equal(t, "XKCDNR", values.Get("field-keywords"))
+	require.Equal(t, "a1b2c3", values.Get("amzn"))
+
+	_, err = parseCaptchaChallenge([]byte("<p>Sorry, we just need to make sure you're not a robot.</p>"), pageURL)
+	require.Error(t, err)
+}
+
+func TestItemsSolvesCaptcha(t *testing.T) {
+	id := "123abc"
+	ts := newCaptchaTestServer(t, id)
+	defer ts.Close()
+
+	var mutex sync.Mutex
+	challenges := []*CaptchaChallenge{}
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+	wishlist.CaptchaSolver = func(challenge *CaptchaChallenge) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		challenges = append(challenges, challenge)
+		return "XKCDNR", nil
+	}
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	require.Len(t, challenges, 1)
+	require.Equal(t, ts.URL+"/captcha/Captcha_abcdef.jpg", challenges[0].ImageURL)
+
+	_, err = wishlist.Name()
+	require.NoError(t, err)
+	require.Len(t, challenges, 1, "should keep the cookie from solving the captcha")
+}
+
+func TestItemsGivesUpOnWrongCaptchaAnswers(t *testing.T) {
+	id := "123abc"
+	ts := newCaptchaTestServer(t, id)
+	defer ts.Close()
+
+	attempts := 0
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+	wishlist.CaptchaSolver = func(challenge *CaptchaChallenge) (string, error) {
+		attempts++
+		return "WRONG", nil
+	}
+
+	_, err = wishlist.Items()
+	require.Equal(t, ErrRobot, err)
+	require.Equal(t, maxCaptchaAttempts, attempts)
+}
+
+func TestItemsCaptchaSolverErrors(t *testing.T) {
+	id := "123abc"
+	ts := newCaptchaTestServer(t, id)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+
+	_, err = wishlist.Items()
+	require.Equal(t, ErrRobot, err, "should fail without a solver")
+
+	wishlist, err = NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+	solverErr := errors.New("no solving today")
+	wishlist.CaptchaSolver = func(challenge *CaptchaChallenge) (string, error) {
+		return "", solverErr
+	}
+	_, err = wishlist.Items()
+	require.Equal(t, solverErr, err)
+}
+
+const captchaHTML = `<html>
+	<body>
+		<h4>Enter the characters you see below</h4>
+		<p class="a-last">Sorry, we just need to make sure you're not a robot.</p>
+		<form method="get" action="/errors/validateCaptcha" name="">
+			<input type=hidden name="amzn" value="a1b2c3" />
+			<input type=hidden name="amzn-r" value="&#047;hz&#047;wishlist&#047;ls&#047;123abc" />
+			<div class="a-row a-text-center">
+				<img src="/captcha/Captcha_abcdef.jpg">
+			</div>
+			<input autocomplete="off" spellcheck="false" placeholder="Type characters" id="captchacharacters" name="field-keywords" type="text">
+			<button type="submit" class="a-button-text">Continue shopping</button>
+		</form>
+	</body>
+</html>`
+
+// newCaptchaTestServer serves a wishlist behind a captcha, whose answer is
+// "XKCDNR". Solving it sets a cookie that lets later requests through.
+func newCaptchaTestServer(t *testing.T, wishlistID string) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/hz/wishlist/ls/"+wishlistID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if _, err := r.Cookie("captcha-passed"); err != nil {
+			w.Write([]byte(captchaHTML))
+			return
+		}
+		w.Write([]byte(wishlistHTML))
+	})
+	mux.HandleFunc("/errors/validateCaptcha", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("amzn") != "a1b2c3" || query.Get("field-keywords") != "XKCDNR" {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(captchaHTML))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "captcha-passed", Value: "1", Path: "/"})
+		http.Redirect(w, r, query.Get("amzn-r"), http.StatusFound)
+	})
+
+	return httptest.NewServer(mux)
+}
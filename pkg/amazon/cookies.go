@@ -0,0 +1,114 @@
+package amazon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// httpOnlyCookiePrefix marks HttpOnly cookies in cookies.txt files as
+	// written by curl and most browser extensions.
+	httpOnlyCookiePrefix = "#HttpOnly_"
+)
+
+// ReadCookiesFile reads cookies from a cookies.txt file in the Netscape format
+// exported by browsers and curl, e.g., to load a signed-in Amazon session.
+func ReadCookiesFile(path string) (http.CookieJar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadCookies(file)
+}
+
+// ReadCookies reads cookies in the Netscape cookies.txt format into a cookie
+// jar that can be given to Wishlist.SetCookieJar.
+func ReadCookies(r io.Reader) (http.CookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		cookie, cookieURL, err := parseCookieLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("Line %d of cookies file: %s", lineNumber, err)
+		}
+		if cookie == nil {
+			continue
+		}
+		jar.SetCookies(cookieURL, []*http.Cookie{cookie})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return jar, nil
+}
+
+// parseCookieLine parses a line of a cookies.txt file, returning the cookie
+// and the URL to set it for. Returns a nil cookie for blank lines and
+// comments.
+func parseCookieLine(line string) (*http.Cookie, *url.URL, error) {
+	line = strings.TrimRight(line, "\r\n")
+	httpOnly := false
+	if strings.HasPrefix(line, httpOnlyCookiePrefix) {
+		httpOnly = true
+		line = line[len(httpOnlyCookiePrefix):]
+	}
+	if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+		return nil, nil, nil
+	}
+
+	fields := strings.Split(line, "\t")
+	if len(fields) != 7 {
+		return nil, nil, fmt.Errorf("expected 7 tab-separated fields, got %d", len(fields))
+	}
+
+	domain := fields[0]
+	includeSubdomains := strings.EqualFold(fields[1], "TRUE")
+	path := fields[2]
+	secure := strings.EqualFold(fields[3], "TRUE")
+
+	expiresUnix, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid expiration time '%s'", fields[4])
+	}
+
+	cookie := &http.Cookie{
+		Name:     fields[5],
+		Value:    fields[6],
+		Path:     path,
+		Secure:   secure,
+		HttpOnly: httpOnly,
+	}
+	// An expiration time of 0 marks a session cookie.
+	if expiresUnix > 0 {
+		cookie.Expires = time.Unix(expiresUnix, 0)
+	}
+
+	host := strings.TrimPrefix(domain, ".")
+	if includeSubdomains {
+		cookie.Domain = host
+	}
+
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+
+	return cookie, &url.URL{Scheme: scheme, Host: host, Path: path}, nil
+}
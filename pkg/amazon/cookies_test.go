@@ -0,0 +1,52 @@
+package amazon
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCookies(t *testing.T) {
+	cookiesTxt := strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		"",
+		".amazon.com\tTRUE\t/\tTRUE\t4102444800\tsession-id\t123-4567890",
+		"#HttpOnly_.amazon.com\tTRUE\t/\tTRUE\t0\tsess-at-main\tabc\"def",
+		"www.amazon.de\tFALSE\t/\tFALSE\t0\tsession-id\t999-0000000",
+		"",
+	}, "\n")
+
+	jar, err := ReadCookies(strings.NewReader(cookiesTxt))
+	require.NoError(t, err)
+
+	uri, err := url.Parse("https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT")
+	require.NoError(t, err)
+	cookies := jar.Cookies(uri)
+	require.Len(t, cookies, 2)
+	require.Equal(t, "session-id", cookies[0].Name)
+	require.Equal(t, "123-4567890", cookies[0].Value)
+	require.Equal(t, "sess-at-main", cookies[1].Name)
+
+	uri, err = url.Parse("http://www.amazon.com/")
+	require.NoError(t, err)
+	require.Len(t, jar.Cookies(uri), 0, "secure cookies should not be sent over HTTP")
+
+	uri, err = url.Parse("https://www.amazon.de/")
+	require.NoError(t, err)
+	cookies = jar.Cookies(uri)
+	require.Len(t, cookies, 1)
+	require.Equal(t, "999-0000000", cookies[0].Value)
+}
+
+func TestReadCookiesErrors(t *testing.T) {
+	_, err := ReadCookies(strings.NewReader(".amazon.com\tTRUE\t/\tTRUE\n"))
+	require.Error(t, err)
+
+	_, err = ReadCookies(strings.NewReader(".amazon.com\tTRUE\t/\tTRUE\tsoon\tsession-id\t123"))
+	require.Error(t, err)
+
+	_, err = ReadCookiesFile("does-not-exist.txt")
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package amazon
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+)
+
+const (
+	// signInPathPrefix is the path of Amazon's sign-in page, where requests
+	// for private lists are redirected on every marketplace.
+	signInPathPrefix = "/ap/signin"
+)
+
+var (
+	// captchaMarkers are bits of markup from the captcha form Amazon shows
+	// robots, the same on every marketplace regardless of language.
+	captchaMarkers = [][]byte{
+		[]byte("/errors/validateCaptcha"),
+		[]byte(`id="captchacharacters"`),
+		[]byte(`name="amzn-captcha-verify"`),
+	}
+
+	// addToCartURLMarkers are parts of the URLs that add-to-cart buttons link
+	// to, regardless of the button's label.
+	addToCartURLMarkers = []string{
+		"submit.addToCart",
+		"/gp/item-dispatch",
+	}
+)
+
+// isRobotPage returns true if the given HTML is Amazon's "are you a robot?"
+// page rather than the page that was requested. The captcha form is checked
+// first so detection works even for marketplaces whose wording we don't know.
+func isRobotPage(body []byte, m *Marketplace) bool {
+	for _, marker := range captchaMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+
+	if m == nil {
+		return false
+	}
+
+	lowerBody := bytes.ToLower(body)
+	for _, message := range m.RobotMessages {
+		if bytes.Contains(lowerBody, []byte(strings.ToLower(message))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSignInPage returns true if a request ended up at Amazon's sign-in page,
+// e.g., after being redirected there from a private list.
+func isSignInPage(uri *url.URL) bool {
+	return uri != nil && strings.HasPrefix(uri.Path, signInPathPrefix)
+}
+
+// isAddToCartLink returns true if a link with the given text and URL found
+// in an item's add-to-cart container adds the item to your shopping cart.
+func isAddToCartLink(text string, href string, m *Marketplace) bool {
+	for _, marker := range addToCartURLMarkers {
+		if strings.Contains(href, marker) {
+			return true
+		}
+	}
+
+	if m == nil {
+		return false
+	}
+
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if len(text) < 1 {
+		return false
+	}
+
+	for _, label := range m.AddToCartTexts {
+		if strings.Contains(text, strings.ToLower(label)) {
+			return true
+		}
+	}
+
+	return false
+}
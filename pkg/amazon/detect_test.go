@@ -0,0 +1,65 @@
+package amazon
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRobotPage(t *testing.T) {
+	tests := []struct {
+		host     string
+		body     string
+		expected bool
+	}{
+		{"www.amazon.com", "<p>Sorry, we just need to make sure you're not a robot.</p>", true},
+		{"www.amazon.fr", "<p>Nous devons simplement nous assurer que vous n'êtes pas un robot.</p>", true},
+		{"www.amazon.de", `<form method="get" action="/errors/validateCaptcha"></form>`, true},
+		{"www.amazon.co.jp", `<input id="captchacharacters" name="field-keywords">`, true},
+		{"www.amazon.com", wishlistHTML, false},
+		{"www.amazon.de", "<p>we just need to make sure you're not a robot</p>", false},
+	}
+
+	for _, test := range tests {
+		m := marketplaceForHost(test.host)
+		require.Equal(t, test.expected, isRobotPage([]byte(test.body), m), test.host)
+	}
+}
+
+func TestIsSignInPage(t *testing.T) {
+	tests := map[string]bool{
+		"https://www.amazon.com/ap/signin?openid.return_to=https%3A%2F%2Fwww.amazon.com%2Fhz%2Fwishlist%2Fls%2F3I6EQPZ8OB1DT": true,
+		"https://www.amazon.de/ap/signin":                     true,
+		"https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT": false,
+	}
+
+	for urlStr, expected := range tests {
+		uri, err := url.Parse(urlStr)
+		require.NoError(t, err)
+		require.Equal(t, expected, isSignInPage(uri), urlStr)
+	}
+	require.False(t, isSignInPage(nil))
+}
+
+func TestIsAddToCartLink(t *testing.T) {
+	tests := []struct {
+		host     string
+		text     string
+		href     string
+		expected bool
+	}{
+		{"www.amazon.com", "\n  Add to Cart\n  ", "/some/path", true},
+		{"www.amazon.co.uk", "Add to Basket", "/some/path", true},
+		{"www.amazon.de", "In den Einkaufswagen", "/some/path", true},
+		{"www.amazon.co.jp", "カートに入れる", "/some/path", true},
+		{"www.amazon.it", "Qualcosa", "/gp/item-dispatch?registryID.1=ABC&submit.addToCart=1", true},
+		{"www.amazon.com", "Buying this gift elsewhere?", "/ap/signin", false},
+		{"www.amazon.de", "Add to Cart", "/some/path", false},
+	}
+
+	for _, test := range tests {
+		m := marketplaceForHost(test.host)
+		require.Equal(t, test.expected, isAddToCartLink(test.text, test.href, m), test.text)
+	}
+}
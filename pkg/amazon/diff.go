@@ -0,0 +1,438 @@
+package amazon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ItemField is a property of an item that Diff compares between snapshots.
+type ItemField int
+
+const (
+	// FieldPrice is the item's price, compared as a number when Amazon's
+	// text can be parsed.
+	FieldPrice ItemField = iota
+
+	// FieldRequestedCount is how many of the item the recipient would like.
+	FieldRequestedCount
+
+	// FieldOwnedCount is how many of the item the recipient already has.
+	FieldOwnedCount
+
+	// FieldPurchasedCount is how many of the item have been bought from a
+	// registry.
+	FieldPurchasedCount
+
+	// FieldPriority is how much the recipient wants the item.
+	FieldPriority
+
+	// FieldComment is the note the list owner wrote about the item.
+	FieldComment
+
+	// FieldAvailability is whether the item can be bought.
+	FieldAvailability
+)
+
+var (
+	itemFields = []ItemField{FieldPrice, FieldRequestedCount, FieldOwnedCount,
+		FieldPurchasedCount, FieldPriority, FieldComment, FieldAvailability}
+
+	itemFieldCodes = map[ItemField]string{
+		FieldPrice:          "price",
+		FieldRequestedCount: "requested_count",
+		FieldOwnedCount:     "owned_count",
+		FieldPurchasedCount: "purchased_count",
+		FieldPriority:       "priority",
+		FieldComment:        "comment",
+		FieldAvailability:   "availability",
+	}
+)
+
+// SnapshotDiff is what changed on a wishlist between two snapshots of it.
+type SnapshotDiff struct {
+	// Old is the earlier snapshot.
+	Old *Snapshot
+
+	// New is the later snapshot.
+	New *Snapshot
+
+	// Added are the items in New that were not in Old, in New's order.
+	Added []*Item
+
+	// Removed are the items in Old that are no longer in New, in Old's
+	// order. Amazon removes items when the owner deletes them, and some
+	// lists hide items once they have been bought.
+	Removed []*Item
+
+	// Changed are the items in both snapshots that differ, in New's order.
+	Changed []*ItemDiff
+}
+
+// ItemDiff is how one item changed between two snapshots.
+type ItemDiff struct {
+	// Old is the item as it was in the earlier snapshot.
+	Old *Item
+
+	// New is the item as it is in the later snapshot.
+	New *Item
+
+	// Fields are the properties of the item that changed.
+	Fields []ItemField
+}
+
+// diffJSON is the JSON representation of a SnapshotDiff.
+type diffJSON struct {
+	ID         string          `json:"id"`
+	OldTakenAt time.Time       `json:"old_taken_at"`
+	NewTakenAt time.Time       `json:"new_taken_at"`
+	Added      []*Item         `json:"added"`
+	Removed    []*Item         `json:"removed"`
+	Changed    []*itemDiffJSON `json:"changed"`
+}
+
+// itemDiffJSON is the JSON representation of an ItemDiff.
+type itemDiffJSON struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Purchased int                `json:"purchased"`
+	Changes   []*fieldChangeJSON `json:"changes"`
+}
+
+// fieldChangeJSON is the old and new value of a field that changed, in the
+// same form as the field's value in the item's own JSON.
+type fieldChangeJSON struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// Diff compares two snapshots of the same wishlist and returns what changed,
+// from items added and removed to changes in price, quantities, priority,
+// comment, and availability. Returns an error if either snapshot is nil or
+// they are of different wishlists.
+func Diff(old, latest *Snapshot) (*SnapshotDiff, error) {
+	if old == nil || latest == nil {
+		return nil, errors.New("Cannot compare a missing snapshot")
+	}
+	if old.ID != latest.ID {
+		return nil, fmt.Errorf("Cannot compare snapshots of different wishlists %s and %s",
+			old.ID, latest.ID)
+	}
+
+	diff := &SnapshotDiff{
+		Old:     old,
+		New:     latest,
+		Added:   []*Item{},
+		Removed: []*Item{},
+		Changed: []*ItemDiff{},
+	}
+
+	oldItems := itemsByID(old)
+	latestItems := itemsByID(latest)
+
+	for _, item := range latest.Items {
+		oldItem := oldItems[item.ID]
+		if oldItem == nil {
+			diff.Added = append(diff.Added, item)
+			continue
+		}
+		if itemDiff := diffItems(oldItem, item); len(itemDiff.Fields) > 0 {
+			diff.Changed = append(diff.Changed, itemDiff)
+		}
+	}
+
+	for _, item := range old.Items {
+		if latestItems[item.ID] == nil {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+
+	return diff, nil
+}
+
+// itemsByID returns the snapshot's items keyed by ID. When an ID appears more
+// than once, the first item with it wins, as with Snapshot.Item.
+func itemsByID(snapshot *Snapshot) map[string]*Item {
+	items := make(map[string]*Item, len(snapshot.Items))
+	for _, item := range snapshot.Items {
+		if _, ok := items[item.ID]; !ok {
+			items[item.ID] = item
+		}
+	}
+	return items
+}
+
+// String returns the name of the field, as used in JSON.
+func (f ItemField) String() string {
+	if code, ok := itemFieldCodes[f]; ok {
+		return code
+	}
+	return "unknown"
+}
+
+// IsEmpty returns true if nothing changed between the snapshots.
+func (d *SnapshotDiff) IsEmpty() bool {
+	return len(d.Added) < 1 && len(d.Removed) < 1 && len(d.Changed) < 1
+}
+
+// Purchased returns the changed items that someone bought: those whose owned
+// or purchased count went up, and those whose requested count was met. Removed items are
+// never included, even though some lists hide items once they have been
+// bought, because Amazon shows no difference between an item that was bought
+// and hidden and one the owner deleted; check Removed for those.
+func (d *SnapshotDiff) Purchased() []*ItemDiff {
+	purchased := []*ItemDiff{}
+	for _, itemDiff := range d.Changed {
+		if itemDiff.Purchased() > 0 || itemDiff.Fulfilled() {
+			purchased = append(purchased, itemDiff)
+		}
+	}
+	return purchased
+}
+
+// String describes the changes one per line, e.g.,
+// "Cat Litter: price $15.96 -> $12.00 (-24.8%)".
+func (d *SnapshotDiff) String() string {
+	if d.IsEmpty() {
+		return "No changes"
+	}
+
+	lines := []string{}
+	for _, item := range d.Added {
+		line := "Added: " + item.Name
+		if item.Price != "" {
+			line += " (" + item.Price + ")"
+		}
+		lines = append(lines, line)
+	}
+	for _, item := range d.Removed {
+		lines = append(lines, "Removed: "+item.Name)
+	}
+	for _, itemDiff := range d.Changed {
+		lines = append(lines, itemDiff.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MarshalJSON writes the diff with snake_case keys. Added and removed items
+// are written as items are, and each change lists its field's old and new
+// values. A missing snapshot or item is written as if it were empty.
+func (d *SnapshotDiff) MarshalJSON() ([]byte, error) {
+	raw := diffJSON{
+		Added:   d.Added,
+		Removed: d.Removed,
+		Changed: make([]*itemDiffJSON, len(d.Changed)),
+	}
+	if raw.Added == nil {
+		raw.Added = []*Item{}
+	}
+	if raw.Removed == nil {
+		raw.Removed = []*Item{}
+	}
+	if d.Old != nil {
+		raw.ID = d.Old.ID
+		raw.OldTakenAt = d.Old.TakenAt
+	}
+	if d.New != nil {
+		raw.ID = d.New.ID
+		raw.NewTakenAt = d.New.TakenAt
+	}
+
+	for i, itemDiff := range d.Changed {
+		if itemDiff.Old == nil || itemDiff.New == nil {
+			raw.Changed[i] = missingItemDiffJSON(itemDiff)
+			continue
+		}
+		changes := make([]*fieldChangeJSON, len(itemDiff.Fields))
+		for j, field := range itemDiff.Fields {
+			changes[j] = &fieldChangeJSON{
+				Field: field.String(),
+				Old:   fieldJSONValue(itemDiff.Old, field),
+				New:   fieldJSONValue(itemDiff.New, field),
+			}
+		}
+		raw.Changed[i] = &itemDiffJSON{
+			ID:        itemDiff.New.ID,
+			Name:      itemDiff.New.Name,
+			Purchased: itemDiff.Purchased(),
+			Changes:   changes,
+		}
+	}
+
+	return json.Marshal(raw)
+}
+
+// missingItemDiffJSON is the JSON representation of an ItemDiff missing its
+// old or new item, with no changes since there is nothing to compare.
+func missingItemDiffJSON(itemDiff *ItemDiff) *itemDiffJSON {
+	raw := &itemDiffJSON{Changes: []*fieldChangeJSON{}}
+	for _, item := range []*Item{itemDiff.Old, itemDiff.New} {
+		if item != nil {
+			raw.ID = item.ID
+			raw.Name = item.Name
+		}
+	}
+	return raw
+}
+
+// HasChanged returns true if the given field of the item changed.
+func (d *ItemDiff) HasChanged(field ItemField) bool {
+	for _, f := range d.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Purchased returns how many of the item were bought between the snapshots,
+// going by how much its owned count, or for registries its purchased count,
+// went up, or 0 if the count is unknown.
+func (d *ItemDiff) Purchased() int {
+	return countIncrease(d.Old.OwnedCount, d.New.OwnedCount) +
+		countIncrease(d.Old.PurchasedCount, d.New.PurchasedCount)
+}
+
+// Fulfilled returns true if the item now has as many as were requested but
+// didn't before, including when the requested count was lowered to the owned
+// count, e.g., after the owner was given one that wasn't bought from the list.
+func (d *ItemDiff) Fulfilled() bool {
+	return isFulfilled(d.New) && !isFulfilled(d.Old)
+}
+
+func isFulfilled(item *Item) bool {
+	return item.RequestedCount > 0 && (item.OwnedCount >= item.RequestedCount ||
+		item.PurchasedCount >= item.RequestedCount)
+}
+
+func countIncrease(old, latest int) int {
+	if old < 0 || latest < 0 || latest < old {
+		return 0
+	}
+	return latest - old
+}
+
+// PriceChangePercent returns by what percentage the item's price changed,
+// e.g., -20 for a 20% drop. Returns false if either price is unknown or they
+// are in different currencies.
+func (d *ItemDiff) PriceChangePercent() (float64, bool) {
+	oldPrice, err := d.Old.ParsedPrice()
+	if err != nil || oldPrice.Amount == 0 {
+		return 0, false
+	}
+	newPrice, err := d.New.ParsedPrice()
+	if err != nil || newPrice.Currency != oldPrice.Currency {
+		return 0, false
+	}
+	return (newPrice.Amount - oldPrice.Amount) / oldPrice.Amount * 100, true
+}
+
+// String describes the item's changes on one line, e.g.,
+// "Cat Litter: owned 1 -> 2 (1 purchased), priority medium -> high".
+func (d *ItemDiff) String() string {
+	changes := make([]string, len(d.Fields))
+	for i, field := range d.Fields {
+		switch field {
+		case FieldPrice:
+			changes[i] = fmt.Sprintf("price %s -> %s", textOrNone(d.Old.Price),
+				textOrNone(d.New.Price))
+			if percent, ok := d.PriceChangePercent(); ok {
+				changes[i] += fmt.Sprintf(" (%+.1f%%)", percent)
+			}
+		case FieldRequestedCount:
+			changes[i] = fmt.Sprintf("requested %d -> %d", d.Old.RequestedCount,
+				d.New.RequestedCount)
+		case FieldOwnedCount:
+			changes[i] = fmt.Sprintf("owned %d -> %d", d.Old.OwnedCount, d.New.OwnedCount)
+			if purchased := d.Purchased(); purchased > 0 {
+				changes[i] += fmt.Sprintf(" (%d purchased)", purchased)
+			}
+		case FieldPurchasedCount:
+			changes[i] = fmt.Sprintf("purchased %d -> %d", d.Old.PurchasedCount,
+				d.New.PurchasedCount)
+		case FieldPriority:
+			changes[i] = fmt.Sprintf("priority %s -> %s", d.Old.Priority, d.New.Priority)
+		case FieldComment:
+			changes[i] = fmt.Sprintf("comment %q -> %q", d.Old.Comment, d.New.Comment)
+		case FieldAvailability:
+			changes[i] = fmt.Sprintf("availability %s -> %s", d.Old.Availability,
+				d.New.Availability)
+		}
+	}
+	return d.New.Name + ": " + strings.Join(changes, ", ")
+}
+
+func diffItems(old, latest *Item) *ItemDiff {
+	itemDiff := &ItemDiff{Old: old, New: latest, Fields: []ItemField{}}
+	for _, field := range itemFields {
+		if fieldChanged(old, latest, field) {
+			itemDiff.Fields = append(itemDiff.Fields, field)
+		}
+	}
+	return itemDiff
+}
+
+func fieldChanged(old, latest *Item, field ItemField) bool {
+	switch field {
+	case FieldPrice:
+		oldPrice, oldErr := old.ParsedPrice()
+		newPrice, newErr := latest.ParsedPrice()
+		if oldErr == nil && newErr == nil {
+			return *oldPrice != *newPrice
+		}
+		if (oldErr == nil) != (newErr == nil) {
+			return true
+		}
+		return strings.TrimSpace(old.Price) != strings.TrimSpace(latest.Price)
+	case FieldRequestedCount:
+		// A count Amazon stopped showing isn't a change we can describe.
+		return old.RequestedCount > -1 && latest.RequestedCount > -1 &&
+			old.RequestedCount != latest.RequestedCount
+	case FieldOwnedCount:
+		return old.OwnedCount > -1 && latest.OwnedCount > -1 &&
+			old.OwnedCount != latest.OwnedCount
+	case FieldPurchasedCount:
+		return old.PurchasedCount > -1 && latest.PurchasedCount > -1 &&
+			old.PurchasedCount != latest.PurchasedCount
+	case FieldPriority:
+		return old.Priority != latest.Priority
+	case FieldComment:
+		return strings.TrimSpace(old.Comment) != strings.TrimSpace(latest.Comment)
+	case FieldAvailability:
+		return old.Availability != latest.Availability
+	}
+	return false
+}
+
+func fieldJSONValue(item *Item, field ItemField) interface{} {
+	switch field {
+	case FieldPrice:
+		if price, err := item.ParsedPrice(); err == nil {
+			return price
+		}
+		return nil
+	case FieldRequestedCount:
+		return nullableCount(item.RequestedCount)
+	case FieldOwnedCount:
+		return nullableCount(item.OwnedCount)
+	case FieldPurchasedCount:
+		return nullableCount(item.PurchasedCount)
+	case FieldPriority:
+		return item.Priority.String()
+	case FieldComment:
+		return item.Comment
+	case FieldAvailability:
+		return availabilityCodes[item.Availability]
+	}
+	return nil
+}
+
+func textOrNone(text string) string {
+	if text = strings.TrimSpace(text); text != "" {
+		return text
+	}
+	return "none"
+}
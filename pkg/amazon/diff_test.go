@@ -0,0 +1,185 @@
+package amazon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newDiffTestItem(id, name, price string) *Item {
+	item := NewItem(id, name, "https://www.amazon.com/dp/B0018CLTKE")
+	item.Price = price
+	item.RequestedCount = 2
+	item.OwnedCount = 0
+	item.Availability = AvailabilityInStock
+	return item
+}
+
+func TestDiff(t *testing.T) {
+	takenAt := time.Date(2020, time.January, 4, 18, 30, 0, 0, time.UTC)
+
+	litter := newDiffTestItem("A", "Cat Litter", "$15.00")
+	toy := newDiffTestItem("B", "Cat Toy", "$5.00")
+	bowl := newDiffTestItem("C", "Food Bowl", "$8.00")
+	old := &Snapshot{ID: "123abc", Items: []*Item{litter, toy, bowl}, TakenAt: takenAt}
+
+	cheaperLitter := newDiffTestItem("A", "Cat Litter", "$12.00")
+	cheaperLitter.OwnedCount = 1
+	cheaperLitter.Priority = PriorityHigh
+	cheaperLitter.Comment = "The unscented kind"
+	sameToy := newDiffTestItem("B", "Cat Toy", "$5.00")
+	sameToy.RawAvailability = "In Stock."
+	brush := newDiffTestItem("D", "Brush", "$3.50")
+	latest := &Snapshot{ID: "123abc", Items: []*Item{brush, cheaperLitter, sameToy},
+		TakenAt: takenAt.Add(24 * time.Hour)}
+
+	diff, err := Diff(old, latest)
+	require.NoError(t, err)
+	require.False(t, diff.IsEmpty())
+	require.Equal(t, []*Item{brush}, diff.Added)
+	require.Equal(t, []*Item{bowl}, diff.Removed)
+	require.Len(t, diff.Changed, 1)
+
+	itemDiff := diff.Changed[0]
+	require.Equal(t, []ItemField{FieldPrice, FieldOwnedCount, FieldPriority, FieldComment}, itemDiff.Fields)
+	require.True(t, itemDiff.HasChanged(FieldPrice))
+	require.False(t, itemDiff.HasChanged(FieldAvailability))
+	require.Equal(t, 1, itemDiff.Purchased())
+	require.Equal(t, []*ItemDiff{itemDiff}, diff.Purchased())
+	percent, ok := itemDiff.PriceChangePercent()
+	require.True(t, ok)
+	require.InDelta(t, -20.0, percent, 0.001)
+
+	require.Equal(t, `Added: Brush ($3.50)
+Removed: Food Bowl
+Cat Litter: price $15.00 -> $12.00 (-20.0%), owned 0 -> 1 (1 purchased), priority medium -> high, comment "" -> "The unscented kind"`,
+		diff.String())
+
+	data, err := json.Marshal(diff)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, "123abc", fields["id"])
+	require.Equal(t, "2020-01-05T18:30:00Z", fields["new_taken_at"])
+	require.Len(t, fields["added"], 1)
+	changed := fields["changed"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, 1.0, changed["purchased"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"field": "price",
+			"old": map[string]interface{}{"amount": 15.0, "currency": "USD"},
+			"new": map[string]interface{}{"amount": 12.0, "currency": "USD"}},
+		map[string]interface{}{"field": "owned_count", "old": 0.0, "new": 1.0},
+		map[string]interface{}{"field": "priority", "old": "medium", "new": "high"},
+		map[string]interface{}{"field": "comment", "old": "", "new": "The unscented kind"},
+	}, changed["changes"])
+}
+
+func TestDiffUnknownValues(t *testing.T) {
+	old := newDiffTestItem("A", "Cat Litter", "")
+	latest := newDiffTestItem("A", "Cat Litter", "Currently unavailable")
+	latest.RequestedCount = -1
+	latest.Availability = AvailabilityUnavailable
+
+	diff, err := Diff(&Snapshot{ID: "123abc", Items: []*Item{old}},
+		&Snapshot{ID: "123abc", Items: []*Item{latest}})
+	require.NoError(t, err)
+	require.Len(t, diff.Changed, 1)
+	require.Equal(t, []ItemField{FieldPrice, FieldAvailability}, diff.Changed[0].Fields)
+	_, ok := diff.Changed[0].PriceChangePercent()
+	require.False(t, ok)
+	require.Equal(t, "Cat Litter: price none -> Currently unavailable, availability in stock -> unavailable",
+		diff.Changed[0].String())
+}
+
+func TestDiffSameSnapshot(t *testing.T) {
+	snapshot := &Snapshot{ID: "123abc", Items: []*Item{newDiffTestItem("A", "Cat Litter", "$15.00")}}
+	diff, err := Diff(snapshot, snapshot)
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+	require.Equal(t, "No changes", diff.String())
+
+	_, err = Diff(snapshot, &Snapshot{ID: "456def"})
+	require.Error(t, err)
+	_, err = Diff(nil, snapshot)
+	require.Error(t, err)
+	_, err = Diff(snapshot, nil)
+	require.Error(t, err)
+}
+
+func TestDiffPurchased(t *testing.T) {
+	unknownOwned := newDiffTestItem("A", "Cat Litter", "$15.00")
+	unknownOwned.OwnedCount = -1
+	partlyBought := newDiffTestItem("B", "Cat Toy", "$5.00")
+	partlyBought.OwnedCount = 1
+	old := &Snapshot{ID: "123abc", Items: []*Item{unknownOwned, partlyBought}}
+
+	fulfilled := newDiffTestItem("A", "Cat Litter", "$15.00")
+	fulfilled.OwnedCount = 2
+	latest := &Snapshot{ID: "123abc", Items: []*Item{fulfilled}}
+
+	diff, err := Diff(old, latest)
+	require.NoError(t, err)
+	require.Empty(t, diff.Changed, "an owned count that was unknown isn't a change")
+	require.Equal(t, []*Item{partlyBought}, diff.Removed)
+	require.Empty(t, diff.Purchased(), "should not guess why an item was removed")
+
+	old.Items[0].OwnedCount = 0
+	diff, err = Diff(old, latest)
+	require.NoError(t, err)
+	require.Len(t, diff.Purchased(), 1)
+	require.True(t, diff.Purchased()[0].Fulfilled())
+	require.Equal(t, 2, diff.Purchased()[0].Purchased())
+
+	old.Items[0].OwnedCount = 2
+	old.Items[0].RequestedCount = 3
+	diff, err = Diff(old, latest)
+	require.NoError(t, err)
+	require.Len(t, diff.Purchased(), 1, "should include an item whose requested count was met")
+	require.Equal(t, 0, diff.Purchased()[0].Purchased())
+}
+
+func TestDiffPurchasedFromRegistry(t *testing.T) {
+	onesie := newDiffTestItem("A", "Onesie", "$9.00")
+	onesie.OwnedCount = -1
+	onesie.PurchasedCount = 1
+	old := &Snapshot{ID: "123abc", Items: []*Item{onesie}}
+
+	bought := newDiffTestItem("A", "Onesie", "$9.00")
+	bought.OwnedCount = -1
+	bought.PurchasedCount = 2
+	latest := &Snapshot{ID: "123abc", Items: []*Item{bought}}
+
+	diff, err := Diff(old, latest)
+	require.NoError(t, err)
+	require.Len(t, diff.Purchased(), 1)
+	require.Equal(t, []ItemField{FieldPurchasedCount}, diff.Changed[0].Fields)
+	require.Equal(t, 1, diff.Changed[0].Purchased())
+	require.True(t, diff.Changed[0].Fulfilled())
+	require.Equal(t, "Onesie: purchased 1 -> 2", diff.String())
+}
+
+func TestDiffJSONMissingSnapshots(t *testing.T) {
+	data, err := json.Marshal(&SnapshotDiff{})
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, "", fields["id"])
+	require.Equal(t, []interface{}{}, fields["added"])
+	require.Equal(t, []interface{}{}, fields["changed"])
+
+	litter := newDiffTestItem("A", "Cat Litter", "$15.00")
+	diff := &SnapshotDiff{
+		Old:     &Snapshot{ID: "123abc", Items: []*Item{litter}},
+		Changed: []*ItemDiff{{Old: litter, Fields: []ItemField{FieldPrice}}},
+	}
+	data, err = json.Marshal(diff)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, "123abc", fields["id"])
+	changed := fields["changed"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "A", changed["id"])
+	require.Equal(t, []interface{}{}, changed["changes"])
+}
@@ -36,6 +36,16 @@ type Item struct {
 	// OwnedCount is how many of the product the wishlist recipient already owns.
 	OwnedCount int
 
+	// PurchasedCount is how many of the product gift-givers have bought from
+	// a Baby or Wedding Registry. Other kinds of list don't show it.
+	PurchasedCount int
+
+	// Comment is the note the list owner wrote about this product.
+	Comment string
+
+	// Priority is how much the wishlist recipient wants this product.
+	Priority Priority
+
 	// Name is the name of this product.
 	Name string
 
@@ -45,13 +55,36 @@ type Item struct {
 	// ID is a unique identifier for this product on Amazon.
 	ID string
 
-	// DateAdded is a string representation of when this item was added to the
-	// wishlist. Example: "October 20, 2019"
+	// ASIN is the Amazon Standard Identification Number of this product.
+	// Example: "B0018CLTKE"
+	ASIN string
+
+	// RawDateAdded is a string representation of when this item was added to
+	// the wishlist, as written by the Amazon marketplace the wishlist is on.
+	// Examples: "October 20, 2019", "20. Oktober 2019"
 	RawDateAdded string
 
 	// Rating is a string description of how Amazon customers have rated this
 	// product.
 	Rating string
+
+	// Byline is the author, artist, or brand credited for this product.
+	// Example: "Jane Doe"
+	Byline string
+
+	// Format is the edition or format of this product, when Amazon lists one
+	// in the byline. Example: "Paperback"
+	Format string
+
+	// Availability indicates whether this product can currently be bought.
+	Availability Availability
+
+	// RawAvailability is the availability note Amazon shows for this product.
+	// Example: "Only 3 left in stock"
+	RawAvailability string
+
+	marketplace  *Marketplace
+	associateTag string
 }
 
 // NewItem constructs an Item with the given product identifier, name, and
@@ -61,25 +94,37 @@ func NewItem(id string, name string, directURL string) *Item {
 		DirectURL:      directURL,
 		Name:           name,
 		ID:             id,
+		ASIN:           ASINFromURL(directURL),
 		IsPrime:        false,
 		ReviewCount:    0,
 		RequestedCount: -1,
 		OwnedCount:     -1,
+		PurchasedCount: -1,
+		Availability:   AvailabilityUnknown,
+		marketplace:    defaultMarketplace(),
 	}
 }
 
-// DateAdded returns the date this item was added to the wishlist.
+// DateAdded returns the date this item was added to the wishlist, at midnight
+// in the timezone of the Amazon marketplace the wishlist is on.
 func (i *Item) DateAdded() (*time.Time, error) {
 	if i.RawDateAdded == "" {
 		return nil, fmt.Errorf("No date added found for item %s", i.ID)
 	}
 
-	date, err := time.Parse("January 2, 2006", i.RawDateAdded)
-	if err != nil {
-		return nil, err
+	marketplace := i.marketplace
+	if marketplace == nil {
+		marketplace = defaultMarketplace()
 	}
 
-	return &date, nil
+	return marketplace.parseDate(i.RawDateAdded)
+}
+
+// Stars returns how many stars out of 5 Amazon customers have given this
+// product on average. The second return value is false if the rating is
+// unknown or could not be parsed.
+func (i *Item) Stars() (float64, bool) {
+	return parseStars(i.Rating)
 }
 
 // URL returns a string URL to this product on Amazon. Prefers the link that
@@ -91,6 +136,49 @@ func (i *Item) URL() string {
 	return i.DirectURL
 }
 
+// CanonicalURL returns the URL to this product's page on Amazon without any
+// tracking parameters, including the Amazon Associates tag of the wishlist
+// the item came from, if any. Falls back to DirectURL without its tracking
+// parameters if the ASIN is unknown.
+func (i *Item) CanonicalURL() string {
+	baseURL, err := baseURLFromURL(i.DirectURL)
+	if i.ASIN == "" || err != nil {
+		return withoutTracking(i.DirectURL, i.associateTag)
+	}
+	return ProductURL(baseURL, i.ASIN, i.associateTag)
+}
+
+// CanonicalAddToCartURL returns AddToCartURL without any tracking parameters,
+// including the Amazon Associates tag of the wishlist the item came from, if
+// any. Returns an empty string if there is no add-to-cart link.
+func (i *Item) CanonicalAddToCartURL() string {
+	if i.AddToCartURL == "" {
+		return ""
+	}
+	return withoutTracking(i.AddToCartURL, i.associateTag)
+}
+
+// CanonicalReviewsURL returns the URL to the customer reviews of this product
+// without any tracking parameters. Falls back to ReviewsURL if the ASIN is
+// unknown.
+func (i *Item) CanonicalReviewsURL() string {
+	baseURL, err := baseURLFromURL(i.DirectURL)
+	if i.ASIN == "" || err != nil {
+		return i.ReviewsURL
+	}
+	return ReviewsURL(baseURL, i.ASIN)
+}
+
+// OfferListingURL returns the URL listing the offers from all sellers of this
+// product, or an empty string if the ASIN is unknown.
+func (i *Item) OfferListingURL() string {
+	baseURL, err := baseURLFromURL(i.DirectURL)
+	if i.ASIN == "" || err != nil {
+		return ""
+	}
+	return OfferListingURL(baseURL, i.ASIN, i.associateTag)
+}
+
 // String returns a description of this product.
 func (i *Item) String() string {
 	var sb strings.Builder
@@ -101,6 +189,21 @@ func (i *Item) String() string {
 		sb.WriteString("\n")
 	}
 
+	if i.Byline != "" {
+		sb.WriteString("\tby ")
+		sb.WriteString(i.Byline)
+		if i.Format != "" {
+			sb.WriteString(" (")
+			sb.WriteString(i.Format)
+			sb.WriteString(")")
+		}
+		sb.WriteString("\n")
+	} else if i.Format != "" {
+		sb.WriteString("\t")
+		sb.WriteString(i.Format)
+		sb.WriteString("\n")
+	}
+
 	line := strings.TrimSpace(strings.Join([]string{
 		i.Price,
 		i.Rating,
@@ -111,6 +214,12 @@ func (i *Item) String() string {
 		sb.WriteString("\n")
 	}
 
+	if i.Comment != "" {
+		sb.WriteString("\tNote: ")
+		sb.WriteString(i.Comment)
+		sb.WriteString("\n")
+	}
+
 	if i.RawDateAdded != "" {
 		sb.WriteString("\tAdded ")
 		sb.WriteString(i.RawDateAdded)
@@ -121,6 +230,12 @@ func (i *Item) String() string {
 		sb.WriteString("\tPrime\n")
 	}
 
+	if i.RawAvailability != "" {
+		sb.WriteString("\t")
+		sb.WriteString(i.RawAvailability)
+		sb.WriteString("\n")
+	}
+
 	if i.ReviewCount > 0 || i.ReviewsURL != "" {
 		sb.WriteString("\t")
 		if i.ReviewCount > 0 {
@@ -166,6 +281,15 @@ func (i *Item) String() string {
 			sb.WriteString(strconv.Itoa(i.OwnedCount))
 		}
 	}
+	if i.PurchasedCount > -1 {
+		if i.RequestedCount > -1 || i.OwnedCount > -1 {
+			sb.WriteString(" / ")
+		} else {
+			sb.WriteString("\t")
+		}
+		sb.WriteString("Purchased: ")
+		sb.WriteString(strconv.Itoa(i.PurchasedCount))
+	}
 
 	return strings.TrimSuffix(sb.String(), "\n")
 }
@@ -0,0 +1,473 @@
+package amazon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+const (
+	// SnapshotVersion is the version of the JSON schema written for wishlist
+	// snapshots, including the items in them; items written on their own
+	// carry no version. It changes whenever a field is renamed or removed or
+	// its meaning changes; adding fields does not change it.
+	SnapshotVersion = 1
+)
+
+var (
+	listKindCodes = map[ListKind]string{
+		ListKindWishlist:        "wishlist",
+		ListKindIdeaList:        "idea_list",
+		ListKindBabyRegistry:    "baby_registry",
+		ListKindWeddingRegistry: "wedding_registry",
+	}
+
+	availabilityCodes = map[Availability]string{
+		AvailabilityUnknown:      "unknown",
+		AvailabilityInStock:      "in_stock",
+		AvailabilityLimited:      "limited",
+		AvailabilityOtherSellers: "other_sellers",
+		AvailabilityUnavailable:  "unavailable",
+	}
+
+	privacyCodes = map[Privacy]string{
+		PrivacyUnknown: "unknown",
+		PrivacyPublic:  "public",
+		PrivacyShared:  "shared",
+		PrivacyPrivate: "private",
+	}
+)
+
+// Snapshot is the state of a wishlist at a point in time: what it is, what
+// Amazon says about it, and the items on it. Snapshots can be written to and
+// read from JSON, see SnapshotVersion.
+type Snapshot struct {
+	// ID is the unique identifier of the wishlist on Amazon.
+	ID string
+
+	// Kind is the type of list.
+	Kind ListKind
+
+	// URL is the canonical URL of the wishlist.
+	URL string
+
+	// Marketplace is the Amazon marketplace the wishlist is on.
+	Marketplace *Marketplace
+
+	// Metadata describes the wishlist as a whole.
+	Metadata Metadata
+
+	// Items are the products on the wishlist, most recently added first.
+	Items []*Item
+
+	// TakenAt is when the wishlist was loaded from Amazon.
+	TakenAt time.Time
+}
+
+// itemJSON is the JSON representation of an Item. Counts that are unknown are
+// null, and values parsed from Amazon's text are included next to the text.
+type itemJSON struct {
+	ID              string     `json:"id"`
+	ASIN            string     `json:"asin,omitempty"`
+	Name            string     `json:"name"`
+	Byline          string     `json:"byline,omitempty"`
+	Format          string     `json:"format,omitempty"`
+	Comment         string     `json:"comment,omitempty"`
+	RawPrice        string     `json:"raw_price,omitempty"`
+	Price           *Price     `json:"price"`
+	RawDateAdded    string     `json:"raw_date_added,omitempty"`
+	DateAdded       *time.Time `json:"date_added"`
+	RawRating       string     `json:"raw_rating,omitempty"`
+	Stars           *float64   `json:"stars"`
+	ReviewCount     int        `json:"review_count"`
+	RequestedCount  *int       `json:"requested_count"`
+	OwnedCount      *int       `json:"owned_count"`
+	PurchasedCount  *int       `json:"purchased_count,omitempty"`
+	Priority        string     `json:"priority"`
+	IsPrime         bool       `json:"is_prime"`
+	Availability    string     `json:"availability"`
+	RawAvailability string     `json:"raw_availability,omitempty"`
+	DirectURL       string     `json:"direct_url,omitempty"`
+	AddToCartURL    string     `json:"add_to_cart_url,omitempty"`
+	ImageURL        string     `json:"image_url,omitempty"`
+	ReviewsURL      string     `json:"reviews_url,omitempty"`
+	Marketplace     string     `json:"marketplace"`
+	AssociateTag    string     `json:"associate_tag,omitempty"`
+}
+
+// metadataJSON is the JSON representation of Metadata.
+type metadataJSON struct {
+	Name               string `json:"name"`
+	OwnerName          string `json:"owner_name,omitempty"`
+	Description        string `json:"description,omitempty"`
+	ItemCount          *int   `json:"item_count"`
+	Privacy            string `json:"privacy"`
+	HasShippingAddress bool   `json:"has_shipping_address"`
+	ShipTo             string `json:"ship_to,omitempty"`
+}
+
+// snapshotJSON is the JSON representation of a Snapshot.
+type snapshotJSON struct {
+	Version     int       `json:"version"`
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	URL         string    `json:"url"`
+	Marketplace string    `json:"marketplace"`
+	Metadata    Metadata  `json:"metadata"`
+	Items       []*Item   `json:"items"`
+	TakenAt     time.Time `json:"taken_at"`
+}
+
+// Snapshot loads the wishlist's metadata and items from Amazon.
+func (w *Wishlist) Snapshot() (*Snapshot, error) {
+	// The metadata is read from the same page loads as the items. It's reset
+	// for each layout tried, since each load reads the header again.
+	metadata := &Metadata{ItemCount: -1}
+	items, err := w.loadItems(func(c *colly.Collector) {
+		*metadata = Metadata{ItemCount: -1}
+		w.onMetadata(c, metadata)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSnapshot(w, *metadata, items), nil
+}
+
+// NewSnapshot returns a snapshot of the given wishlist with the given
+// metadata and items, taken now.
+func NewSnapshot(w *Wishlist, metadata Metadata, items map[string]*Item) *Snapshot {
+	snapshot := &Snapshot{
+		ID:          w.id,
+		Kind:        w.kind,
+		URL:         w.CanonicalURL(),
+		Marketplace: w.marketplace,
+		Metadata:    metadata,
+		Items:       make([]*Item, 0, len(items)),
+		TakenAt:     time.Now().UTC(),
+	}
+	for _, item := range items {
+		snapshot.Items = append(snapshot.Items, item)
+	}
+	sortItems(snapshot.Items)
+	return snapshot
+}
+
+// Item returns the item with the given ID from the snapshot, or nil if the
+// wishlist did not have that item.
+func (s *Snapshot) Item(id string) *Item {
+	for _, item := range s.Items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// MarshalJSON writes the snapshot, including the schema version.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	domain := ""
+	if s.Marketplace != nil {
+		domain = s.Marketplace.Domain
+	}
+	items := s.Items
+	if items == nil {
+		items = []*Item{}
+	}
+
+	return json.Marshal(snapshotJSON{
+		Version:     SnapshotVersion,
+		ID:          s.ID,
+		Kind:        listKindCodes[s.Kind],
+		URL:         s.URL,
+		Marketplace: domain,
+		Metadata:    s.Metadata,
+		Items:       items,
+		TakenAt:     s.TakenAt,
+	})
+}
+
+// UnmarshalJSON reads a snapshot written by MarshalJSON. Returns an error if
+// it was written with a newer schema version than this package knows.
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	raw := snapshotJSON{Metadata: Metadata{ItemCount: -1}}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.Version < 1 {
+		return errors.New("Snapshot has no schema version")
+	}
+	if raw.Version > SnapshotVersion {
+		return fmt.Errorf("Snapshot schema version %d is newer than supported version %d",
+			raw.Version, SnapshotVersion)
+	}
+
+	kind, err := parseListKindCode(raw.Kind)
+	if err != nil {
+		return err
+	}
+
+	marketplace, err := marketplaceForDomain(raw.Marketplace)
+	if err != nil {
+		return err
+	}
+
+	*s = Snapshot{
+		ID:          raw.ID,
+		Kind:        kind,
+		URL:         raw.URL,
+		Marketplace: marketplace,
+		Metadata:    raw.Metadata,
+		Items:       raw.Items,
+		TakenAt:     raw.TakenAt,
+	}
+	if s.Items == nil {
+		s.Items = []*Item{}
+	}
+	return nil
+}
+
+// MarshalJSON writes the item with snake_case keys, null for unknown counts,
+// and the parsed price, date added, and rating alongside Amazon's text. Items
+// are only versioned inside a Snapshot; an item written on its own has no
+// version, so store snapshots rather than items to be able to read them back
+// after the schema changes.
+func (i Item) MarshalJSON() ([]byte, error) {
+	raw := itemJSON{
+		ID:              i.ID,
+		ASIN:            i.ASIN,
+		Name:            i.Name,
+		Byline:          i.Byline,
+		Format:          i.Format,
+		Comment:         i.Comment,
+		RawPrice:        i.Price,
+		RawDateAdded:    i.RawDateAdded,
+		RawRating:       i.Rating,
+		ReviewCount:     i.ReviewCount,
+		RequestedCount:  nullableCount(i.RequestedCount),
+		OwnedCount:      nullableCount(i.OwnedCount),
+		PurchasedCount:  nullableCount(i.PurchasedCount),
+		Priority:        i.Priority.String(),
+		IsPrime:         i.IsPrime,
+		Availability:    availabilityCodes[i.Availability],
+		RawAvailability: i.RawAvailability,
+		DirectURL:       i.DirectURL,
+		AddToCartURL:    i.AddToCartURL,
+		ImageURL:        i.ImageURL,
+		ReviewsURL:      i.ReviewsURL,
+		AssociateTag:    i.associateTag,
+	}
+
+	if i.marketplace != nil {
+		raw.Marketplace = i.marketplace.Domain
+	} else {
+		raw.Marketplace = defaultMarketplace().Domain
+	}
+	if price, err := i.ParsedPrice(); err == nil {
+		raw.Price = price
+	}
+	if dateAdded, err := i.DateAdded(); err == nil {
+		raw.DateAdded = dateAdded
+	}
+	if stars, ok := i.Stars(); ok {
+		raw.Stars = &stars
+	}
+
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON reads an item written by MarshalJSON. The parsed price, date
+// added, and rating are recomputed from Amazon's text rather than read.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	var raw itemJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	availability, err := parseAvailabilityCode(raw.Availability)
+	if err != nil {
+		return err
+	}
+
+	priority, err := parsePriorityCode(raw.Priority)
+	if err != nil {
+		return err
+	}
+
+	marketplace, err := marketplaceForDomain(raw.Marketplace)
+	if err != nil {
+		return err
+	}
+
+	*i = Item{
+		ID:              raw.ID,
+		ASIN:            raw.ASIN,
+		Name:            raw.Name,
+		Byline:          raw.Byline,
+		Format:          raw.Format,
+		Comment:         raw.Comment,
+		Price:           raw.RawPrice,
+		RawDateAdded:    raw.RawDateAdded,
+		Rating:          raw.RawRating,
+		ReviewCount:     raw.ReviewCount,
+		RequestedCount:  countOrUnknown(raw.RequestedCount),
+		OwnedCount:      countOrUnknown(raw.OwnedCount),
+		PurchasedCount:  countOrUnknown(raw.PurchasedCount),
+		Priority:        priority,
+		IsPrime:         raw.IsPrime,
+		Availability:    availability,
+		RawAvailability: raw.RawAvailability,
+		DirectURL:       raw.DirectURL,
+		AddToCartURL:    raw.AddToCartURL,
+		ImageURL:        raw.ImageURL,
+		ReviewsURL:      raw.ReviewsURL,
+		marketplace:     marketplace,
+		associateTag:    raw.AssociateTag,
+	}
+	return nil
+}
+
+// MarshalJSON writes the metadata with snake_case keys and null for an
+// unknown item count.
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	return json.Marshal(metadataJSON{
+		Name:               m.Name,
+		OwnerName:          m.OwnerName,
+		Description:        m.Description,
+		ItemCount:          nullableCount(m.ItemCount),
+		Privacy:            privacyCodes[m.Privacy],
+		HasShippingAddress: m.HasShippingAddress,
+		ShipTo:             m.ShipTo,
+	})
+}
+
+// UnmarshalJSON reads metadata written by MarshalJSON.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	var raw metadataJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	privacy, err := parsePrivacyCode(raw.Privacy)
+	if err != nil {
+		return err
+	}
+
+	*m = Metadata{
+		Name:               raw.Name,
+		OwnerName:          raw.OwnerName,
+		Description:        raw.Description,
+		ItemCount:          countOrUnknown(raw.ItemCount),
+		Privacy:            privacy,
+		HasShippingAddress: raw.HasShippingAddress,
+		ShipTo:             raw.ShipTo,
+	}
+	return nil
+}
+
+// sortItems orders items most recently added first, as Amazon does by
+// default, falling back to their IDs so the order is stable. Each item's date
+// is parsed once, up front, rather than on every comparison.
+func sortItems(items []*Item) {
+	dates := make(map[*Item]*time.Time, len(items))
+	for _, item := range items {
+		if dateAdded, err := item.DateAdded(); err == nil {
+			dates[item] = dateAdded
+		}
+	}
+
+	sort.SliceStable(items, func(a, b int) bool {
+		dateA, dateB := dates[items[a]], dates[items[b]]
+		if dateA != nil && dateB != nil && !dateA.Equal(*dateB) {
+			return dateA.After(*dateB)
+		}
+		if (dateA != nil) != (dateB != nil) {
+			return dateA != nil
+		}
+		return items[a].ID < items[b].ID
+	})
+}
+
+// marketplaceForDomain returns the registered marketplace with the given
+// domain, e.g., "amazon.de", or the default marketplace if the domain is
+// empty. Returns an error for unknown domains, rather than reading prices and
+// dates in the wrong marketplace's format.
+func marketplaceForDomain(domain string) (*Marketplace, error) {
+	if domain == "" {
+		return defaultMarketplace(), nil
+	}
+
+	marketplacesMu.RLock()
+	defer marketplacesMu.RUnlock()
+
+	m, ok := marketplaceByDomain[strings.ToLower(domain)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown marketplace '%s', see RegisterMarketplace", domain)
+	}
+	return m, nil
+}
+
+func nullableCount(count int) *int {
+	if count < 0 {
+		return nil
+	}
+	return &count
+}
+
+func countOrUnknown(count *int) int {
+	if count == nil {
+		return -1
+	}
+	return *count
+}
+
+func parseListKindCode(code string) (ListKind, error) {
+	for kind, kindCode := range listKindCodes {
+		if code == kindCode {
+			return kind, nil
+		}
+	}
+	return ListKindWishlist, fmt.Errorf("Unknown list kind '%s'", code)
+}
+
+func parseAvailabilityCode(code string) (Availability, error) {
+	if code == "" {
+		return AvailabilityUnknown, nil
+	}
+	for availability, availabilityCode := range availabilityCodes {
+		if code == availabilityCode {
+			return availability, nil
+		}
+	}
+	return AvailabilityUnknown, fmt.Errorf("Unknown availability '%s'", code)
+}
+
+func parsePriorityCode(code string) (Priority, error) {
+	if code == "" {
+		return PriorityMedium, nil
+	}
+	for priority := PriorityLowest; priority <= PriorityHighest; priority++ {
+		if code == priority.String() {
+			return priority, nil
+		}
+	}
+	return PriorityMedium, fmt.Errorf("Unknown priority '%s'", code)
+}
+
+func parsePrivacyCode(code string) (Privacy, error) {
+	if code == "" {
+		return PrivacyUnknown, nil
+	}
+	for privacy, privacyCode := range privacyCodes {
+		if code == privacyCode {
+			return privacy, nil
+		}
+	}
+	return PrivacyUnknown, fmt.Errorf("Unknown privacy '%s'", code)
+}
@@ -0,0 +1,191 @@
+package amazon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemJSON(t *testing.T) {
+	item := NewItem("I2G6UJO0FYWV8J", "Purina Tidy Cats Non-Clumping Cat Litter",
+		"https://www.amazon.de/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J")
+	item.marketplace = marketplaceForHost("www.amazon.de")
+	item.associateTag = "mysite-21"
+	item.Price = "15,96 €"
+	item.RawDateAdded = "10. Juli 2019"
+	item.Rating = "4,0 von 5 Sternen"
+	item.ReviewCount = 930
+	item.OwnedCount = 11
+	item.Priority = PriorityHighest
+	item.IsPrime = true
+	item.Availability = AvailabilityLimited
+	item.RawAvailability = "Nur noch 3 auf Lager"
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, "I2G6UJO0FYWV8J", fields["id"])
+	require.Equal(t, "B0018CLTKE", fields["asin"])
+	require.Equal(t, "15,96 €", fields["raw_price"])
+	require.Equal(t, map[string]interface{}{"amount": 15.96, "currency": "EUR"}, fields["price"])
+	require.Equal(t, "2019-07-10T00:00:00+02:00", fields["date_added"])
+	require.Equal(t, 4.0, fields["stars"])
+	require.Nil(t, fields["requested_count"])
+	require.Contains(t, fields, "requested_count")
+	require.Equal(t, 11.0, fields["owned_count"])
+	require.NotContains(t, fields, "purchased_count", "only registries count purchases")
+	require.Equal(t, "limited", fields["availability"])
+	require.Equal(t, "highest", fields["priority"])
+	require.Equal(t, "amazon.de", fields["marketplace"])
+
+	var decoded Item
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, item, &decoded)
+
+	byValue, err := json.Marshal(*item)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(byValue), "should write values as pointers are")
+
+	roundTripped, err := json.Marshal(&decoded)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(roundTripped))
+}
+
+func TestItemJSONUnknownValues(t *testing.T) {
+	item := NewItem("I2G6UJO0FYWV8J", "Cat Litter", "")
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": "I2G6UJO0FYWV8J",
+		"name": "Cat Litter",
+		"price": null,
+		"date_added": null,
+		"stars": null,
+		"review_count": 0,
+		"requested_count": null,
+		"owned_count": null,
+		"priority": "medium",
+		"is_prime": false,
+		"availability": "unknown",
+		"marketplace": "amazon.com"
+	}`, string(data))
+
+	var decoded Item
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, -1, decoded.RequestedCount)
+	require.Equal(t, -1, decoded.OwnedCount)
+	require.Equal(t, -1, decoded.PurchasedCount)
+
+	require.Error(t, json.Unmarshal([]byte(`{"id": "1", "availability": "maybe"}`), &decoded))
+}
+
+func TestWishlistSnapshotJSON(t *testing.T) {
+	id := "123abc"
+	ts := newTestServer(t, id)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	snapshot, err := wishlist.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, id, snapshot.ID)
+	require.Equal(t, "NHA Wish List", snapshot.Metadata.Name)
+	require.Len(t, snapshot.Items, 1)
+	require.NotNil(t, snapshot.Item("I2G6UJO0FYWV8J"))
+	require.Nil(t, snapshot.Item("nope"))
+	require.WithinDuration(t, time.Now(), snapshot.TakenAt, time.Minute)
+
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+
+	byValue, err := json.Marshal(*snapshot)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(byValue), "should write values as pointers are")
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, float64(SnapshotVersion), fields["version"])
+	require.Equal(t, "wishlist", fields["kind"])
+	require.Equal(t, ts.URL+"/hz/wishlist/ls/123abc", fields["url"])
+	metadata := fields["metadata"].(map[string]interface{})
+	require.Equal(t, "NHA Wish List", metadata["name"])
+	require.Equal(t, "unknown", metadata["privacy"])
+	require.Nil(t, metadata["item_count"])
+
+	var decoded Snapshot
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, snapshot.ID, decoded.ID)
+	require.Equal(t, snapshot.Kind, decoded.Kind)
+	require.Equal(t, snapshot.Metadata, decoded.Metadata)
+	require.True(t, snapshot.TakenAt.Equal(decoded.TakenAt))
+	require.Equal(t, snapshot.Items[0], decoded.Items[0])
+
+	roundTripped, err := json.Marshal(&decoded)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(roundTripped))
+}
+
+func TestWishlistSnapshotLoadsPageOnce(t *testing.T) {
+	id := "123abc"
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(wishlistHTML))
+	}))
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+
+	snapshot, err := wishlist.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, "NHA Wish List", snapshot.Metadata.Name)
+	require.Len(t, snapshot.Items, 1)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestSnapshotJSONVersion(t *testing.T) {
+	var snapshot Snapshot
+	require.NoError(t, json.Unmarshal([]byte(`{"version": 1, "id": "123abc", "kind": "baby_registry", "marketplace": "amazon.co.uk"}`), &snapshot))
+	require.Equal(t, ListKindBabyRegistry, snapshot.Kind)
+	require.Equal(t, "amazon.co.uk", snapshot.Marketplace.Domain)
+	require.Equal(t, "", snapshot.Metadata.Name)
+	require.Equal(t, -1, snapshot.Metadata.ItemCount)
+	require.Empty(t, snapshot.Items)
+
+	require.Error(t, json.Unmarshal([]byte(`{"id": "123abc", "kind": "wishlist"}`), &snapshot))
+	require.Error(t, json.Unmarshal([]byte(`{"version": 99, "id": "123abc", "kind": "wishlist"}`), &snapshot))
+	require.Error(t, json.Unmarshal([]byte(`{"version": 1, "id": "123abc", "kind": "shopping_cart"}`), &snapshot))
+	require.Error(t, json.Unmarshal([]byte(`{"version": 1, "id": "123abc", "kind": "wishlist", "metadata": {"privacy": "secret"}}`), &snapshot))
+	require.Error(t, json.Unmarshal([]byte(`{"version": 1, "id": "123abc", "kind": "wishlist", "marketplace": "amazon.example"}`), &snapshot))
+
+	var item Item
+	require.Error(t, json.Unmarshal([]byte(`{"id": "I2G6UJO0FYWV8J", "marketplace": "amazon.example"}`), &item))
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "I2G6UJO0FYWV8J", "marketplace": "amazon.de"}`), &item))
+	require.Equal(t, "amazon.de", item.marketplace.Domain)
+}
+
+func TestSortItems(t *testing.T) {
+	older := NewItem("B", "Older", "")
+	older.RawDateAdded = "July 10, 2019"
+	newer := NewItem("C", "Newer", "")
+	newer.RawDateAdded = "October 20, 2019"
+	undated := NewItem("A", "Undated", "")
+
+	items := []*Item{undated, older, newer}
+	sortItems(items)
+	require.Equal(t, []*Item{newer, older, undated}, items)
+}
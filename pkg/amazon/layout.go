@@ -0,0 +1,124 @@
+package amazon
+
+import (
+	"strings"
+
+	"github.com/gocolly/colly"
+)
+
+// Layout is one of the ways Amazon can show a wishlist, each with its own
+// markup to parse.
+type Layout int
+
+const (
+	// LayoutStandard is the default list view, loaded a page at a time.
+	LayoutStandard Layout = iota
+
+	// LayoutGrid shows items as tiles, loaded a page at a time.
+	LayoutGrid
+
+	// LayoutPrintView is the printer-friendly view, which lists every item
+	// on a single page but with fewer details, e.g., no ratings.
+	LayoutPrintView
+)
+
+const (
+	itemNameIDPrefix = "itemName_"
+)
+
+// DefaultLayouts returns the layouts tried by Items when a wishlist doesn't
+// specify any: the standard view, then the grid, then the print view. Each
+// fallback is only requested when the views before it yielded no items.
+func DefaultLayouts() []Layout {
+	return []Layout{LayoutStandard, LayoutGrid, LayoutPrintView}
+}
+
+// String returns the name of the layout.
+func (l Layout) String() string {
+	switch l {
+	case LayoutStandard:
+		return "standard"
+	case LayoutGrid:
+		return "grid"
+	case LayoutPrintView:
+		return "print"
+	}
+	return "unknown"
+}
+
+func (w *Wishlist) layoutURL(layout Layout) string {
+	return getWishlistURL(w.baseURL, w.id, w.kind, w.query, layout)
+}
+
+// onLayoutItems registers the callbacks that read items from the given
+// layout's markup.
+func (w *Wishlist) onLayoutItems(c *colly.Collector, layout Layout) {
+	if parser, ok := registryParsers[w.kind]; ok {
+		w.onRegistryItems(c, parser)
+		return
+	}
+
+	switch layout {
+	case LayoutGrid:
+		c.OnHTML(".wl-grid-items li[data-itemid]", w.onGridItem)
+	case LayoutPrintView:
+		c.OnHTML("tr[data-itemid]", w.onPrintViewItem)
+		// The print view has every item on one page, so there's no need to
+		// follow links to more pages.
+		return
+	default:
+		c.OnHTML("ul li", w.onListItem)
+	}
+
+	c.OnHTML("a.wl-see-more", func(link *colly.HTMLElement) {
+		w.onLoadMoreLink(c, link)
+	})
+}
+
+func (w *Wishlist) onGridItem(gridItem *colly.HTMLElement) {
+	id := gridItem.Attr("data-itemid")
+	if len(id) < 1 {
+		return
+	}
+
+	gridItem.ForEach("a", func(index int, link *colly.HTMLElement) {
+		if link.Attr("id") != itemNameIDPrefix+id {
+			return
+		}
+
+		name := link.Attr("title")
+		if len(name) < 1 {
+			name = strings.TrimSpace(link.Text)
+		}
+
+		relativeURL := link.Attr("href")
+		if len(name) < 1 || len(relativeURL) < 1 {
+			return
+		}
+
+		w.addItem(id, name, link.Request.AbsoluteURL(relativeURL))
+	})
+	w.onItemDetails(id, gridItem)
+}
+
+func (w *Wishlist) onPrintViewItem(row *colly.HTMLElement) {
+	id := row.Attr("data-itemid")
+	if len(id) < 1 {
+		return
+	}
+
+	name := strings.TrimSpace(row.ChildText("#" + itemNameIDPrefix + id))
+	if len(name) < 1 {
+		return
+	}
+
+	// The print view doesn't link to products, so build the link from the
+	// product's ASIN when it's given.
+	directURL := ""
+	if asin := row.Attr("data-asin"); len(asin) > 0 {
+		directURL = ProductURL(w.baseURL, asin, "")
+	}
+
+	w.addItem(id, name, directURL)
+	w.onItemDetails(id, row)
+}
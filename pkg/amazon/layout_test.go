@@ -0,0 +1,218 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemsGridLayout(t *testing.T) {
+	id := "123abc"
+	ts := newLayoutTestServer(t, id, emptyWishlistHTML)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutGrid}
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Contains(t, wishlist.URLs()[0], "layout=grid")
+
+	item := items["I2G6UJO0FYWV8J"]
+	require.NotNil(t, item)
+	require.Equal(t, "Purina Tidy Cats Non-Clumping Cat Litter", item.Name)
+	require.Equal(t, "$15.96", item.Price)
+	require.Equal(t, "B0018CLTKE", item.ASIN)
+	require.Equal(t, "https://images-na.ssl-images-amazon.com/images/I/81YphWp9eIL._SS135_.jpg", item.ImageURL)
+	require.True(t, item.IsPrime)
+
+	item = items["IXYZ123456789"]
+	require.NotNil(t, item)
+	require.Equal(t, "The Go Programming Language", item.Name)
+	require.Equal(t, "$32.99", item.Price)
+	require.False(t, item.IsPrime)
+}
+
+func TestItemsPrintViewLayout(t *testing.T) {
+	id := "123abc"
+	ts := newLayoutTestServer(t, id, emptyWishlistHTML)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutPrintView}
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Contains(t, wishlist.URLs()[0], "/hz/wishlist/printview/"+id)
+
+	item := items["I2G6UJO0FYWV8J"]
+	require.NotNil(t, item)
+	require.Equal(t, "Purina Tidy Cats Non-Clumping Cat Litter", item.Name)
+	require.Equal(t, ts.URL+"/dp/B0018CLTKE", item.DirectURL)
+	require.Equal(t, "$15.96", item.Price)
+	require.Equal(t, "Purina Tidy Cats", item.Byline)
+	require.Equal(t, 50, item.RequestedCount)
+	require.Equal(t, 11, item.OwnedCount)
+	require.Equal(t, "July 10, 2019", item.RawDateAdded)
+	require.Equal(t, "https://images-na.ssl-images-amazon.com/images/I/81YphWp9eIL._SS135_.jpg", item.ImageURL)
+}
+
+func TestItemsFallsBackToNextLayout(t *testing.T) {
+	id := "123abc"
+	ts := newLayoutTestServer(t, id, emptyWishlistHTML)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard, LayoutPrintView}
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Contains(t, wishlist.URLs()[0], "/hz/wishlist/printview/"+id)
+}
+
+func TestItemsFallsBackByDefault(t *testing.T) {
+	id := "123abc"
+	ts := newLayoutTestServer(t, id, emptyWishlistHTML)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Contains(t, wishlist.URLs()[0], "layout=grid")
+}
+
+func TestItemsTriesEveryDefaultLayout(t *testing.T) {
+	id := "123abc"
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(emptyWishlistHTML))
+	}))
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Empty(t, items)
+	require.Equal(t, 3, requests, "should request the standard, grid, and print views")
+}
+
+func TestItemsDoesNotFallBackWhenStandardHasItems(t *testing.T) {
+	id := "123abc"
+	ts := newLayoutTestServer(t, id, wishlistHTML)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Contains(t, wishlist.URLs()[0], "layout=standard")
+}
+
+const emptyWishlistHTML = `<!doctype html>
+<html>
+	<body>
+		<span id="profile-list-name">NHA Wish List</span>
+		<ul id="g-items" class="a-unordered-list a-nostyle a-vertical a-spacing-none g-items-section"></ul>
+	</body>
+</html>`
+
+const gridWishlistHTML = `<!doctype html>
+<html>
+	<body>
+		<span id="profile-list-name">NHA Wish List</span>
+		<ul id="g-items-grid" class="a-unordered-list a-nostyle wl-grid-items">
+			<li class="a-spacing-none wl-grid-item" data-id="3I6EQPZ8OB1DT" data-itemid="I2G6UJO0FYWV8J" data-price="15.96">
+				<div id="itemImage_I2G6UJO0FYWV8J" class="wl-grid-item-image">
+					<a class="a-link-normal" href="/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&amp;colid=3I6EQPZ8OB1DT&amp;psc=1&amp;ref_=lv_ov_lig_dp_it_im"><img alt="Purina Tidy Cats Non-Clumping Cat Litter" src="https://images-na.ssl-images-amazon.com/images/I/81YphWp9eIL._SS135_.jpg"/></a>
+				</div>
+				<div class="wl-grid-item-middle-section">
+					<a id="itemName_I2G6UJO0FYWV8J" class="a-link-normal" href="/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&amp;colid=3I6EQPZ8OB1DT&amp;psc=1&amp;ref_=lv_ov_lig_dp_it">
+						Purina Tidy Cats Non-Clumping Cat Litter
+					</a>
+				</div>
+				<div class="wl-grid-item-bottom-section">
+					<span id="itemPrice_I2G6UJO0FYWV8J" class="a-price"><span class="a-offscreen">$15.96</span><span aria-hidden="true">$15.96</span></span>
+					<i class="a-icon a-icon-prime a-icon-small" role="img"></i>
+				</div>
+			</li>
+			<li class="a-spacing-none wl-grid-item" data-id="3I6EQPZ8OB1DT" data-itemid="IXYZ123456789" data-price="32.99">
+				<div id="itemImage_IXYZ123456789" class="wl-grid-item-image">
+					<a class="a-link-normal" href="/dp/0134190440/?coliid=IXYZ123456789&amp;colid=3I6EQPZ8OB1DT"><img alt="" src="https://images-na.ssl-images-amazon.com/images/I/41aSIRXgaAL._SS135_.jpg"/></a>
+				</div>
+				<div class="wl-grid-item-middle-section">
+					<a id="itemName_IXYZ123456789" class="a-link-normal" title="The Go Programming Language" href="/dp/0134190440/?coliid=IXYZ123456789&amp;colid=3I6EQPZ8OB1DT">The Go Programming...</a>
+				</div>
+				<div class="wl-grid-item-bottom-section">
+					<span id="itemPrice_IXYZ123456789" class="a-price"><span class="a-offscreen">$32.99</span></span>
+				</div>
+			</li>
+		</ul>
+	</body>
+</html>`
+
+const printViewHTML = `<!doctype html>
+<html>
+	<body>
+		<h1 class="a-size-large">NHA Wish List</h1>
+		<table id="g-print-items" class="a-bordered a-horizontal-stripes">
+			<tr>
+				<th></th><th>Item</th><th>Price</th><th>Quantity</th><th>Has</th><th>Added</th>
+			</tr>
+			<tr class="g-print-view-row" data-itemid="I2G6UJO0FYWV8J" data-asin="B0018CLTKE">
+				<td class="g-print-view-image"><img src="https://images-na.ssl-images-amazon.com/images/I/81YphWp9eIL._SS135_.jpg"/></td>
+				<td>
+					<span id="itemName_I2G6UJO0FYWV8J" class="a-text-bold">Purina Tidy Cats Non-Clumping Cat Litter</span><br/>
+					<span id="item-byline-I2G6UJO0FYWV8J" class="a-size-small">by Purina Tidy Cats (Misc.)</span>
+				</td>
+				<td><span id="itemPrice_I2G6UJO0FYWV8J" class="a-price"><span class="a-offscreen">$15.96</span></span></td>
+				<td><span id="itemRequested_I2G6UJO0FYWV8J">50</span></td>
+				<td><span id="itemPurchased_I2G6UJO0FYWV8J">11</span></td>
+				<td class="dateAddedText"><span id="itemAddedDate_I2G6UJO0FYWV8J">Added July 10, 2019</span></td>
+			</tr>
+		</table>
+	</body>
+</html>`
+
+// newLayoutTestServer serves the grid and print views of a wishlist, and the
+// given HTML for the standard view.
+func newLayoutTestServer(t *testing.T, wishlistID string, standardHTML string) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/hz/wishlist/ls/"+wishlistID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Query().Get("layout") == "grid" {
+			w.Write([]byte(gridWishlistHTML))
+		} else {
+			w.Write([]byte(standardHTML))
+		}
+	})
+	mux.HandleFunc("/hz/wishlist/printview/"+wishlistID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(printViewHTML))
+	})
+
+	return httptest.NewServer(mux)
+}
@@ -0,0 +1,715 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	englishMonths = []string{"January", "February", "March", "April", "May",
+		"June", "July", "August", "September", "October", "November", "December"}
+	frenchMonths = []string{"janvier", "février", "mars", "avril", "mai", "juin",
+		"juillet", "août", "septembre", "octobre", "novembre", "décembre"}
+	spanishMonths = []string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+		"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+
+	englishUSLayouts = []string{"January 2, 2006", "2 January 2006"}
+	englishUKLayouts = []string{"2 January 2006", "January 2, 2006"}
+
+	englishAddedPrefixes   = []string{"Added "}
+	englishAddToCartTexts  = []string{"Add to Cart", "Add to Basket"}
+	englishRobotMessages   = []string{"we just need to make sure you're not a robot"}
+	frenchAddedPrefixes    = []string{"Ajouté le ", "Ajouté "}
+	frenchAddToCartTexts   = []string{"Ajouter au panier"}
+	frenchRobotMessages    = []string{"nous devons simplement nous assurer que vous n'êtes pas un robot"}
+	spanishAddToCartTexts  = []string{"Agregar al carrito", "Añadir a la cesta"}
+	spanishRobotMessages   = []string{"solo tenemos que asegurarnos de que no eres un robot", "necesitamos asegurarnos de que no eres un robot"}
+	dutchAddedPrefixes     = []string{"Toegevoegd op ", "Toegevoegd "}
+	dutchAddToCartTexts    = []string{"In winkelwagen", "Toevoegen aan winkelwagen"}
+	dutchRobotMessages     = []string{"we moeten er alleen zeker van zijn dat je geen robot bent"}
+	englishPrivateLabels   = []string{"Private"}
+	englishSharedLabels    = []string{"Shared"}
+	englishPublicLabels    = []string{"Public"}
+	frenchPrivateLabels    = []string{"Privée", "Privé"}
+	frenchSharedLabels     = []string{"Partagée", "Partagé"}
+	frenchPublicLabels     = []string{"Publique", "Public"}
+	spanishPrivateLabels   = []string{"Privada", "Privado"}
+	spanishSharedLabels    = []string{"Compartida", "Compartido"}
+	spanishPublicLabels    = []string{"Pública", "Público"}
+	dutchPrivateLabels     = []string{"Privé"}
+	dutchSharedLabels      = []string{"Gedeeld"}
+	dutchPublicLabels      = []string{"Openbaar"}
+	englishOwnerPrefixes   = []string{"by "}
+	englishShipToPrefixes  = []string{"Ship to:"}
+	frenchOwnerPrefixes    = []string{"par "}
+	frenchShipToPrefixes   = []string{"Livrer à :", "Livrer à:"}
+	spanishOwnerPrefixes   = []string{"de "}
+	spanishShipToPrefixes  = []string{"Enviar a:"}
+	dutchOwnerPrefixes     = []string{"door ", "van "}
+	dutchShipToPrefixes    = []string{"Verzenden naar:"}
+	marketplacesMu         sync.RWMutex
+	marketplaces           []*Marketplace
+	marketplaceByDomain    map[string]*Marketplace
+	locationsMu            sync.RWMutex
+	locations              = map[string]*time.Location{}
+	errNoMarketplaceDomain = errors.New("Marketplace has no domain")
+)
+
+func init() {
+	marketplaceByDomain = make(map[string]*Marketplace)
+
+	for _, m := range []*Marketplace{
+		{
+			Domain: "amazon.ae", Country: "AE", Currency: "AED", Language: "en-AE",
+			Timezone: "Asia/Dubai", DateLayouts: englishUKLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.ca", Country: "CA", Currency: "CAD", Language: "en-CA",
+			Timezone: "America/Toronto", Months: frenchMonths,
+			DateLayouts:         englishUSLayouts,
+			AddedPrefixes:       append(englishAddedPrefixes, frenchAddedPrefixes...),
+			AddToCartTexts:      append(englishAddToCartTexts, frenchAddToCartTexts...),
+			RobotMessages:       append(englishRobotMessages, frenchRobotMessages...),
+			AvailabilityPhrases: append(englishAvailabilityPhrases, frenchAvailabilityPhrases...),
+			PrivateLabels:       append(englishPrivateLabels, frenchPrivateLabels...),
+			SharedLabels:        append(englishSharedLabels, frenchSharedLabels...),
+			PublicLabels:        append(englishPublicLabels, frenchPublicLabels...),
+			OwnerPrefixes:       append(englishOwnerPrefixes, frenchOwnerPrefixes...),
+			ShipToPrefixes:      append(englishShipToPrefixes, frenchShipToPrefixes...),
+		},
+		{
+			Domain: "amazon.co.jp", Country: "JP", Currency: "JPY", Language: "ja-JP",
+			Timezone: "Asia/Tokyo", DateLayouts: []string{"2006年1月2日", "2006/01/02"},
+			AddedSuffixes:  []string{"に追加"},
+			AddToCartTexts: []string{"カートに入れる"},
+			RobotMessages:  []string{"ロボットでないことを確認"},
+			AvailabilityPhrases: []AvailabilityPhrase{
+				{"在庫切れ", AvailabilityUnavailable},
+				{"現在お取り扱いできません", AvailabilityUnavailable},
+				{"こちらからもご購入いただけます", AvailabilityOtherSellers},
+				{"残り", AvailabilityLimited},
+				{"在庫あり", AvailabilityInStock},
+			},
+			PrivateLabels:  []string{"非公開"},
+			SharedLabels:   []string{"共有"},
+			PublicLabels:   []string{"公開"},
+			ShipToPrefixes: []string{"お届け先:"},
+		},
+		{
+			Domain: "amazon.co.uk", Country: "GB", Currency: "GBP", Language: "en-GB",
+			Timezone: "Europe/London", DateLayouts: englishUKLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.com", Country: "US", Currency: "USD", Language: "en-US",
+			Timezone: "America/Los_Angeles", DateLayouts: englishUSLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.com.au", Country: "AU", Currency: "AUD", Language: "en-AU",
+			Timezone: "Australia/Sydney", DateLayouts: englishUKLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.com.be", Country: "BE", Currency: "EUR", Language: "fr-BE",
+			Timezone: "Europe/Brussels", Months: frenchMonths,
+			DateLayouts:         englishUKLayouts,
+			AddedPrefixes:       append(frenchAddedPrefixes, dutchAddedPrefixes...),
+			AddToCartTexts:      append(frenchAddToCartTexts, dutchAddToCartTexts...),
+			RobotMessages:       append(frenchRobotMessages, dutchRobotMessages...),
+			AvailabilityPhrases: append(frenchAvailabilityPhrases, dutchAvailabilityPhrases...),
+			PrivateLabels:       append(frenchPrivateLabels, dutchPrivateLabels...),
+			SharedLabels:        append(frenchSharedLabels, dutchSharedLabels...),
+			PublicLabels:        append(frenchPublicLabels, dutchPublicLabels...),
+			OwnerPrefixes:       append(frenchOwnerPrefixes, dutchOwnerPrefixes...),
+			ShipToPrefixes:      append(frenchShipToPrefixes, dutchShipToPrefixes...),
+		},
+		{
+			Domain: "amazon.com.br", Country: "BR", Currency: "BRL", Language: "pt-BR",
+			Timezone: "America/Sao_Paulo",
+			Months: []string{"janeiro", "fevereiro", "março", "abril", "maio",
+				"junho", "julho", "agosto", "setembro", "outubro", "novembro",
+				"dezembro"},
+			DateLayouts:    []string{"2 de January de 2006", "2 January 2006"},
+			AddedPrefixes:  []string{"Adicionado em ", "Adicionado "},
+			AddToCartTexts: []string{"Adicionar ao carrinho"},
+			RobotMessages:  []string{"precisamos verificar se você não é um robô"},
+			AvailabilityPhrases: []AvailabilityPhrase{
+				{"não disponível", AvailabilityUnavailable},
+				{"disponível com estes vendedores", AvailabilityOtherSellers},
+				{"restam apenas", AvailabilityLimited},
+				{"em estoque", AvailabilityInStock},
+			},
+			PrivateLabels:  []string{"Privada", "Privado"},
+			SharedLabels:   []string{"Compartilhada", "Compartilhado"},
+			PublicLabels:   []string{"Pública", "Público"},
+			OwnerPrefixes:  []string{"por "},
+			ShipToPrefixes: []string{"Enviar para:"},
+		},
+		{
+			Domain: "amazon.com.mx", Country: "MX", Currency: "MXN", Language: "es-MX",
+			Timezone: "America/Mexico_City", Months: spanishMonths,
+			DateLayouts:         []string{"2 de January de 2006", "2 January 2006"},
+			AddedPrefixes:       []string{"Agregado el ", "Añadido el ", "Agregado "},
+			AddToCartTexts:      spanishAddToCartTexts,
+			RobotMessages:       spanishRobotMessages,
+			AvailabilityPhrases: spanishAvailabilityPhrases,
+			PrivateLabels:       spanishPrivateLabels,
+			SharedLabels:        spanishSharedLabels,
+			PublicLabels:        spanishPublicLabels,
+			OwnerPrefixes:       spanishOwnerPrefixes,
+			ShipToPrefixes:      spanishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.com.tr", Country: "TR", Currency: "TRY", Language: "tr-TR",
+			Timezone: "Europe/Istanbul",
+			Months: []string{"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran",
+				"Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"},
+			DateLayouts:    []string{"2 January 2006"},
+			AddedPrefixes:  []string{"Eklendi "},
+			AddedSuffixes:  []string{" tarihinde eklendi"},
+			AddToCartTexts: []string{"Sepete Ekle"},
+			RobotMessages:  []string{"robot olmadığınızdan emin olmamız gerekiyor"},
+			AvailabilityPhrases: []AvailabilityPhrase{
+				{"stokta yok", AvailabilityUnavailable},
+				{"şu anda mevcut değil", AvailabilityUnavailable},
+				{"stokta sadece", AvailabilityLimited},
+				{"stokta var", AvailabilityInStock},
+			},
+			PrivateLabels:  []string{"Özel"},
+			SharedLabels:   []string{"Paylaşılan"},
+			PublicLabels:   []string{"Herkese açık"},
+			ShipToPrefixes: []string{"Gönderim adresi:"},
+		},
+		{
+			Domain: "amazon.de", Country: "DE", Currency: "EUR", Language: "de-DE",
+			Timezone: "Europe/Berlin",
+			Months: []string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+				"Juli", "August", "September", "Oktober", "November", "Dezember"},
+			DateLayouts:    []string{"2. January 2006", "2 January 2006"},
+			AddedPrefixes:  []string{"Hinzugefügt am ", "Hinzugefügt "},
+			AddToCartTexts: []string{"In den Einkaufswagen"},
+			RobotMessages:  []string{"sicherstellen, dass Sie kein Roboter sind", "dass Sie kein Roboter sind"},
+			AvailabilityPhrases: []AvailabilityPhrase{
+				{"derzeit nicht verfügbar", AvailabilityUnavailable},
+				{"nicht auf lager", AvailabilityUnavailable},
+				{"erhältlich bei diesen anbietern", AvailabilityOtherSellers},
+				{"alle kaufoptionen", AvailabilityOtherSellers},
+				{"nur noch", AvailabilityLimited},
+				{"auf lager", AvailabilityInStock},
+			},
+			PrivateLabels:  []string{"Privat"},
+			SharedLabels:   []string{"Geteilt"},
+			PublicLabels:   []string{"Öffentlich"},
+			OwnerPrefixes:  []string{"von "},
+			ShipToPrefixes: []string{"Lieferung an:"},
+		},
+		{
+			Domain: "amazon.eg", Country: "EG", Currency: "EGP", Language: "en-EG",
+			Timezone: "Africa/Cairo", DateLayouts: englishUKLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.es", Country: "ES", Currency: "EUR", Language: "es-ES",
+			Timezone: "Europe/Madrid", Months: spanishMonths,
+			DateLayouts:         []string{"2 de January de 2006", "2 January 2006"},
+			AddedPrefixes:       []string{"Añadido el ", "Añadido "},
+			AddToCartTexts:      spanishAddToCartTexts,
+			RobotMessages:       spanishRobotMessages,
+			AvailabilityPhrases: spanishAvailabilityPhrases,
+			PrivateLabels:       spanishPrivateLabels,
+			SharedLabels:        spanishSharedLabels,
+			PublicLabels:        spanishPublicLabels,
+			OwnerPrefixes:       spanishOwnerPrefixes,
+			ShipToPrefixes:      spanishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.fr", Country: "FR", Currency: "EUR", Language: "fr-FR",
+			Timezone: "Europe/Paris", Months: frenchMonths,
+			DateLayouts:         []string{"2 January 2006"},
+			AddedPrefixes:       frenchAddedPrefixes,
+			AddToCartTexts:      frenchAddToCartTexts,
+			RobotMessages:       frenchRobotMessages,
+			AvailabilityPhrases: frenchAvailabilityPhrases,
+			PrivateLabels:       frenchPrivateLabels,
+			SharedLabels:        frenchSharedLabels,
+			PublicLabels:        frenchPublicLabels,
+			OwnerPrefixes:       frenchOwnerPrefixes,
+			ShipToPrefixes:      frenchShipToPrefixes,
+		},
+		{
+			Domain: "amazon.in", Country: "IN", Currency: "INR", Language: "en-IN",
+			Timezone: "Asia/Kolkata", DateLayouts: englishUKLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.it", Country: "IT", Currency: "EUR", Language: "it-IT",
+			Timezone: "Europe/Rome",
+			Months: []string{"gennaio", "febbraio", "marzo", "aprile", "maggio",
+				"giugno", "luglio", "agosto", "settembre", "ottobre", "novembre",
+				"dicembre"},
+			DateLayouts:    []string{"2 January 2006"},
+			AddedPrefixes:  []string{"Aggiunto il ", "Aggiunto l'", "Aggiunto "},
+			AddToCartTexts: []string{"Aggiungi al carrello"},
+			RobotMessages:  []string{"dobbiamo solo accertarci che tu non sia un robot"},
+			AvailabilityPhrases: []AvailabilityPhrase{
+				{"non disponibile", AvailabilityUnavailable},
+				{"disponibile presso questi venditori", AvailabilityOtherSellers},
+				{"ne rimangono solo", AvailabilityLimited},
+				{"disponibilità immediata", AvailabilityInStock},
+			},
+			PrivateLabels:  []string{"Privata", "Privato"},
+			SharedLabels:   []string{"Condivisa", "Condiviso"},
+			PublicLabels:   []string{"Pubblica", "Pubblico"},
+			OwnerPrefixes:  []string{"di "},
+			ShipToPrefixes: []string{"Spedisci a:"},
+		},
+		{
+			Domain: "amazon.nl", Country: "NL", Currency: "EUR", Language: "nl-NL",
+			Timezone: "Europe/Amsterdam",
+			Months: []string{"januari", "februari", "maart", "april", "mei", "juni",
+				"juli", "augustus", "september", "oktober", "november", "december"},
+			DateLayouts:         []string{"2 January 2006"},
+			AddedPrefixes:       dutchAddedPrefixes,
+			AddToCartTexts:      dutchAddToCartTexts,
+			RobotMessages:       dutchRobotMessages,
+			AvailabilityPhrases: dutchAvailabilityPhrases,
+			PrivateLabels:       dutchPrivateLabels,
+			SharedLabels:        dutchSharedLabels,
+			PublicLabels:        dutchPublicLabels,
+			OwnerPrefixes:       dutchOwnerPrefixes,
+			ShipToPrefixes:      dutchShipToPrefixes,
+		},
+		{
+			Domain: "amazon.pl", Country: "PL", Currency: "PLN", Language: "pl-PL",
+			Timezone: "Europe/Warsaw",
+			Months: []string{"stycznia", "lutego", "marca", "kwietnia", "maja",
+				"czerwca", "lipca", "sierpnia", "września", "października",
+				"listopada", "grudnia"},
+			DateLayouts:    []string{"2 January 2006"},
+			AddedPrefixes:  []string{"Dodano "},
+			AddToCartTexts: []string{"Dodaj do koszyka"},
+			RobotMessages:  []string{"upewnić, że nie jesteś robotem"},
+			AvailabilityPhrases: []AvailabilityPhrase{
+				{"niedostępny", AvailabilityUnavailable},
+				{"zostało tylko", AvailabilityLimited},
+				{"w magazynie", AvailabilityInStock},
+			},
+			PrivateLabels:  []string{"Prywatna"},
+			SharedLabels:   []string{"Udostępniona"},
+			PublicLabels:   []string{"Publiczna"},
+			OwnerPrefixes:  []string{"od "},
+			ShipToPrefixes: []string{"Wyślij do:"},
+		},
+		{
+			Domain: "amazon.sa", Country: "SA", Currency: "SAR", Language: "en-SA",
+			Timezone: "Asia/Riyadh", DateLayouts: englishUKLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+		{
+			Domain: "amazon.se", Country: "SE", Currency: "SEK", Language: "sv-SE",
+			Timezone: "Europe/Stockholm",
+			Months: []string{"januari", "februari", "mars", "april", "maj", "juni",
+				"juli", "augusti", "september", "oktober", "november", "december"},
+			DateLayouts:    []string{"2 January 2006"},
+			AddedPrefixes:  []string{"Tillagd den ", "Tillagd "},
+			AddToCartTexts: []string{"Lägg i varukorgen"},
+			RobotMessages:  []string{"vi behöver bara se till att du inte är en robot"},
+			AvailabilityPhrases: []AvailabilityPhrase{
+				{"inte tillgänglig", AvailabilityUnavailable},
+				{"inte i lager", AvailabilityUnavailable},
+				{"kvar i lager", AvailabilityLimited},
+				{"i lager", AvailabilityInStock},
+			},
+			PrivateLabels:  []string{"Privat"},
+			SharedLabels:   []string{"Delad"},
+			PublicLabels:   []string{"Offentlig"},
+			OwnerPrefixes:  []string{"av "},
+			ShipToPrefixes: []string{"Skicka till:"},
+		},
+		{
+			Domain: "amazon.sg", Country: "SG", Currency: "SGD", Language: "en-SG",
+			Timezone: "Asia/Singapore", DateLayouts: englishUKLayouts,
+			AddedPrefixes: englishAddedPrefixes, AddToCartTexts: englishAddToCartTexts,
+			RobotMessages: englishRobotMessages, AvailabilityPhrases: englishAvailabilityPhrases,
+			PrivateLabels: englishPrivateLabels, SharedLabels: englishSharedLabels,
+			PublicLabels:  englishPublicLabels,
+			OwnerPrefixes: englishOwnerPrefixes, ShipToPrefixes: englishShipToPrefixes,
+		},
+	} {
+		if err := RegisterMarketplace(m); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Marketplace describes one of Amazon's country-specific stores, e.g.,
+// amazon.de, along with how it writes the text we look for on wishlists.
+type Marketplace struct {
+	// Domain is the domain of the marketplace without any subdomain.
+	// Example: "amazon.co.uk"
+	Domain string
+
+	// Country is the ISO 3166-1 alpha-2 code of the marketplace's country.
+	// Example: "GB"
+	Country string
+
+	// Currency is the ISO 4217 code of the currency prices are shown in.
+	// Example: "GBP"
+	Currency string
+
+	// Language is the BCP 47 tag of the marketplace's default language.
+	// Example: "en-GB"
+	Language string
+
+	// Timezone is the IANA name of the marketplace's timezone, used for the
+	// dates items were added. Example: "Europe/London"
+	Timezone string
+
+	// DateLayouts are the time.Parse layouts the marketplace writes dates in,
+	// using English month names; localized names are translated via Months
+	// before parsing. Example: "2 January 2006"
+	DateLayouts []string
+
+	// Months are the localized month names, January first. Leave empty for
+	// marketplaces that write months in English or as numbers.
+	Months []string
+
+	// AddedPrefixes and AddedSuffixes surround the date an item was added to
+	// a wishlist. Examples: "Added ", "に追加"
+	AddedPrefixes []string
+	AddedSuffixes []string
+
+	// AddToCartTexts are the labels of the button to add an item to your
+	// shopping cart. Example: "Add to Basket"
+	AddToCartTexts []string
+
+	// AvailabilityPhrases tell what the availability note shown for an item
+	// says about it, checked in order, so phrases that contain others must
+	// come first. Example: {"left in stock", AvailabilityLimited}
+	AvailabilityPhrases []AvailabilityPhrase
+
+	// RobotMessages are phrases from the page Amazon shows when it thinks it's
+	// talking to a robot.
+	RobotMessages []string
+
+	// PrivateLabels, SharedLabels, and PublicLabels say who can see a list in
+	// the list switcher and the wishlist's header.
+	// Examples: "Private", "Shared", "Public"
+	PrivateLabels []string
+	SharedLabels  []string
+	PublicLabels  []string
+
+	// OwnerPrefixes introduce the name of the person a wishlist belongs to.
+	// Example: "by "
+	OwnerPrefixes []string
+
+	// ShipToPrefixes introduce the description of the shipping address
+	// attached to a wishlist. Example: "Ship to:"
+	ShipToPrefixes []string
+}
+
+// RegisterMarketplace makes a marketplace known to the package, so wishlists
+// on its domain are parsed with its settings. Registering a marketplace with
+// the same domain as an existing one replaces it. The package keeps a copy of
+// the marketplace, with its domain normalized to lowercase without a "www."
+// prefix; later changes to m, or to the slices it holds, have no effect.
+func RegisterMarketplace(m *Marketplace) error {
+	if m == nil || len(m.Domain) < 1 {
+		return errNoMarketplaceDomain
+	}
+
+	registered := m.copy()
+	registered.Domain = strings.ToLower(strings.TrimPrefix(m.Domain, "www."))
+
+	marketplacesMu.Lock()
+	defer marketplacesMu.Unlock()
+
+	unregisterMarketplace(registered.Domain)
+	marketplaceByDomain[registered.Domain] = registered
+	marketplaces = append(marketplaces, registered)
+	sort.SliceStable(marketplaces, func(i, j int) bool {
+		return len(marketplaces[i].Domain) > len(marketplaces[j].Domain)
+	})
+
+	return nil
+}
+
+// copy returns a copy of the marketplace that shares none of its slices.
+func (m *Marketplace) copy() *Marketplace {
+	c := *m
+	c.DateLayouts = copyStrings(m.DateLayouts)
+	c.Months = copyStrings(m.Months)
+	c.AddedPrefixes = copyStrings(m.AddedPrefixes)
+	c.AddedSuffixes = copyStrings(m.AddedSuffixes)
+	c.AddToCartTexts = copyStrings(m.AddToCartTexts)
+	c.RobotMessages = copyStrings(m.RobotMessages)
+	c.PrivateLabels = copyStrings(m.PrivateLabels)
+	c.SharedLabels = copyStrings(m.SharedLabels)
+	c.PublicLabels = copyStrings(m.PublicLabels)
+	c.OwnerPrefixes = copyStrings(m.OwnerPrefixes)
+	c.ShipToPrefixes = copyStrings(m.ShipToPrefixes)
+	if m.AvailabilityPhrases != nil {
+		c.AvailabilityPhrases = make([]AvailabilityPhrase, len(m.AvailabilityPhrases))
+		copy(c.AvailabilityPhrases, m.AvailabilityPhrases)
+	}
+	return &c
+}
+
+// unregisterMarketplace forgets the marketplace with the given normalized
+// domain, if any. It must be called with marketplacesMu held.
+func unregisterMarketplace(domain string) {
+	if _, ok := marketplaceByDomain[domain]; !ok {
+		return
+	}
+	delete(marketplaceByDomain, domain)
+	for i, existing := range marketplaces {
+		if existing.Domain == domain {
+			marketplaces = append(marketplaces[:i], marketplaces[i+1:]...)
+			break
+		}
+	}
+}
+
+// Marketplaces returns copies of all known Amazon marketplaces, sorted by
+// domain. Changing them has no effect; use RegisterMarketplace instead.
+func Marketplaces() []*Marketplace {
+	marketplacesMu.RLock()
+	defer marketplacesMu.RUnlock()
+
+	result := make([]*Marketplace, len(marketplaces))
+	for i, m := range marketplaces {
+		result[i] = m.copy()
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Domain < result[j].Domain
+	})
+
+	return result
+}
+
+// MarketplaceForHost returns a copy of the marketplace for the given host
+// name, e.g., "www.amazon.com.br" or "smile.amazon.com". When several
+// marketplaces match, the one with the longest domain wins, so
+// "amazon.com.br" is preferred over "amazon.com". The second return value is
+// false if no marketplace matches.
+func MarketplaceForHost(host string) (*Marketplace, bool) {
+	if m, ok := lookupMarketplace(host); ok {
+		return m.copy(), true
+	}
+	return nil, false
+}
+
+// lookupMarketplace is MarketplaceForHost without the copy, for use within the
+// package, which never changes a registered marketplace.
+func lookupMarketplace(host string) (*Marketplace, bool) {
+	host = strings.ToLower(host)
+
+	marketplacesMu.RLock()
+	defer marketplacesMu.RUnlock()
+
+	for _, m := range marketplaces {
+		if host == m.Domain || strings.HasSuffix(host, "."+m.Domain) {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// marketplaceForHost returns the marketplace for the given host name, falling
+// back to the marketplace at DefaultAmazonDomain for unknown hosts.
+func marketplaceForHost(host string) *Marketplace {
+	if m, ok := lookupMarketplace(host); ok {
+		return m
+	}
+	return defaultMarketplace()
+}
+
+func defaultMarketplace() *Marketplace {
+	m, _ := lookupMarketplace(strings.TrimPrefix(DefaultAmazonDomain, "https://"))
+	return m
+}
+
+// URL returns the URL to the marketplace's home page.
+func (m *Marketplace) URL() string {
+	return fmt.Sprintf("https://www.%s", m.Domain)
+}
+
+func (m *Marketplace) String() string {
+	return m.Domain
+}
+
+// trimAdded strips the localized text around the date an item was added, e.g.,
+// "Hinzugefügt am 10. Juli 2019" becomes "10. Juli 2019".
+func (m *Marketplace) trimAdded(text string) string {
+	text = trimAnyPrefix(strings.TrimSpace(text), m.AddedPrefixes)
+	for _, suffix := range m.AddedSuffixes {
+		if strings.HasSuffix(text, suffix) {
+			text = strings.TrimSuffix(text, suffix)
+			break
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// trimOwner strips the localized text before a wishlist owner's name, e.g.,
+// "von Jane Doe" becomes "Jane Doe".
+func (m *Marketplace) trimOwner(text string) string {
+	return strings.TrimSpace(trimAnyPrefix(collapseSpace(text), m.OwnerPrefixes))
+}
+
+// trimShipTo strips the localized label before the description of a
+// wishlist's shipping address, e.g., "Ship to: Jane Doe's Gift Registry
+// Address" becomes "Jane Doe's Gift Registry Address".
+func (m *Marketplace) trimShipTo(text string) string {
+	return strings.TrimSpace(trimAnyPrefix(collapseSpace(text), m.ShipToPrefixes))
+}
+
+// parsePrivacy determines who can see a list from the label shown next to it
+// in the list switcher or the wishlist's header, e.g., "Privat". Private and
+// shared labels are checked first, since in some languages they contain the
+// public one.
+func (m *Marketplace) parsePrivacy(text string) Privacy {
+	text = strings.ToLower(text)
+	for _, labels := range []struct {
+		privacy Privacy
+		labels  []string
+	}{
+		{PrivacyPrivate, m.PrivateLabels},
+		{PrivacyShared, m.SharedLabels},
+		{PrivacyPublic, m.PublicLabels},
+	} {
+		for _, label := range labels.labels {
+			if strings.Contains(text, strings.ToLower(label)) {
+				return labels.privacy
+			}
+		}
+	}
+	return PrivacyUnknown
+}
+
+// parseDate parses a localized date such as "10 juillet 2019" or
+// "2019年7月10日" as midnight in the marketplace's timezone.
+func (m *Marketplace) parseDate(text string) (*time.Time, error) {
+	value := m.translateMonths(m.trimAdded(text))
+	location, err := m.location()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layout := range m.DateLayouts {
+		date, err := time.ParseInLocation(layout, value, location)
+		if err == nil {
+			return &date, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Could not parse date '%s'", text)
+}
+
+// translateMonths replaces localized month names with English ones so the
+// result can be handled by time.Parse.
+func (m *Marketplace) translateMonths(text string) string {
+	if len(m.Months) < 1 {
+		return text
+	}
+
+	words := strings.Fields(text)
+	for i, word := range words {
+		if word == "1er" {
+			// French writes the first of the month as an ordinal.
+			words[i] = "1"
+			continue
+		}
+		trimmed := strings.TrimRight(word, ".,")
+		for month, name := range m.Months {
+			if strings.EqualFold(trimmed, name) {
+				words[i] = englishMonths[month] + word[len(trimmed):]
+				break
+			}
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// location returns the marketplace's timezone, or an error if the system's
+// timezone database doesn't have it. Timezones are loaded once and reused,
+// since loading one reads the timezone database.
+func (m *Marketplace) location() (*time.Location, error) {
+	if m.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	locationsMu.RLock()
+	location, ok := locations[m.Timezone]
+	locationsMu.RUnlock()
+	if ok {
+		return location, nil
+	}
+
+	location, err := time.LoadLocation(m.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load timezone %s of %s: %s", m.Timezone,
+			m.Domain, err)
+	}
+
+	locationsMu.Lock()
+	locations[m.Timezone] = location
+	locationsMu.Unlock()
+
+	return location, nil
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	result := make([]string, len(values))
+	copy(result, values)
+	return result
+}
+
+// trimAnyPrefix removes the first of the given prefixes that text starts with.
+func trimAnyPrefix(text string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return strings.TrimPrefix(text, prefix)
+		}
+	}
+	return text
+}
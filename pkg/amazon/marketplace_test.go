@@ -0,0 +1,211 @@
+package amazon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		host     string
+		text     string
+		timezone string
+	}{
+		{"www.amazon.ca", "Added July 10, 2019", "America/Toronto"},
+		{"www.amazon.ca", "Ajouté le 10 juillet 2019", "America/Toronto"},
+		{"www.amazon.co.jp", "2019年7月10日に追加", "Asia/Tokyo"},
+		{"www.amazon.co.uk", "Added 10 July 2019", "Europe/London"},
+		{"www.amazon.com", "Added July 10, 2019", "America/Los_Angeles"},
+		{"www.amazon.com.br", "Adicionado em 10 de julho de 2019", "America/Sao_Paulo"},
+		{"www.amazon.de", "Hinzugefügt am 10. Juli 2019", "Europe/Berlin"},
+		{"www.amazon.es", "Añadido el 10 de julio de 2019", "Europe/Madrid"},
+		{"www.amazon.fr", "Ajouté le 10 juillet 2019", "Europe/Paris"},
+		{"www.amazon.in", "Added 10 July 2019", "Asia/Kolkata"},
+		{"www.amazon.it", "Aggiunto il 10 luglio 2019", "Europe/Rome"},
+	}
+
+	for _, test := range tests {
+		location, err := time.LoadLocation(test.timezone)
+		require.NoError(t, err)
+		expected := time.Date(2019, 7, 10, 0, 0, 0, 0, location)
+
+		date, err := marketplaceForHost(test.host).parseDate(test.text)
+		require.NoError(t, err, test.host)
+		require.True(t, expected.Equal(*date), "%s: got %s", test.host, date)
+		require.Equal(t, test.timezone, date.Location().String(), test.host)
+	}
+}
+
+func TestParseDateFirstOfMonthInFrench(t *testing.T) {
+	date, err := marketplaceForHost("www.amazon.fr").parseDate("Ajouté le 1er mars 2020")
+	require.NoError(t, err)
+	require.Equal(t, time.March, date.Month())
+	require.Equal(t, 1, date.Day())
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	_, err := marketplaceForHost("www.amazon.de").parseDate("Hinzugefügt gestern")
+	require.Error(t, err)
+}
+
+func TestParsePrivacy(t *testing.T) {
+	tests := []struct {
+		host     string
+		text     string
+		expected Privacy
+	}{
+		{"www.amazon.com", "Public", PrivacyPublic},
+		{"www.amazon.com", "· Shared", PrivacyShared},
+		{"www.amazon.com", "Private", PrivacyPrivate},
+		{"www.amazon.com", "", PrivacyUnknown},
+		{"www.amazon.de", "Öffentlich", PrivacyPublic},
+		{"www.amazon.de", "Privat", PrivacyPrivate},
+		{"www.amazon.fr", "Partagée", PrivacyShared},
+		{"www.amazon.co.jp", "非公開", PrivacyPrivate},
+		{"www.amazon.co.jp", "公開", PrivacyPublic},
+		{"www.amazon.de", "Shared", PrivacyUnknown},
+	}
+
+	for _, test := range tests {
+		m, ok := MarketplaceForHost(test.host)
+		require.True(t, ok, test.host)
+		require.Equal(t, test.expected, m.parsePrivacy(test.text), test.text)
+	}
+}
+
+func TestMarketplacesReturnsCopies(t *testing.T) {
+	m, ok := MarketplaceForHost("www.amazon.de")
+	require.True(t, ok)
+	m.Currency = "XXX"
+	m.Months[0] = "Jan"
+
+	for _, listed := range Marketplaces() {
+		if listed.Domain == "amazon.de" {
+			listed.DateLayouts[0] = "2006-01-02"
+		}
+	}
+
+	wishlist, err := NewWishlist("https://www.amazon.de/hz/wishlist/ls/123abc")
+	require.NoError(t, err)
+	wishlist.Marketplace().AddedPrefixes[0] = "Added "
+
+	de, ok := MarketplaceForHost("www.amazon.de")
+	require.True(t, ok)
+	require.Equal(t, "EUR", de.Currency)
+	require.Equal(t, "Januar", de.Months[0])
+	require.NotEqual(t, "2006-01-02", de.DateLayouts[0])
+	require.NotEqual(t, "Added ", de.AddedPrefixes[0])
+}
+
+func TestTrimOwnerAndShipTo(t *testing.T) {
+	us, ok := MarketplaceForHost("www.amazon.com")
+	require.True(t, ok)
+	require.Equal(t, "Jane Doe", us.trimOwner("by\n\t\tJane Doe"))
+	require.Equal(t, "Jane Doe's Gift Registry Address",
+		us.trimShipTo("Ship to: Jane Doe's Gift Registry Address"))
+
+	de, ok := MarketplaceForHost("www.amazon.de")
+	require.True(t, ok)
+	require.Equal(t, "Jane Doe", de.trimOwner("von Jane Doe"))
+	require.Equal(t, "Jane Doe", de.trimShipTo("Lieferung an: Jane Doe"))
+
+	fr, ok := MarketplaceForHost("www.amazon.fr")
+	require.True(t, ok)
+	require.Equal(t, "Jeanne Dupont", fr.trimOwner("par Jeanne Dupont"))
+	require.Equal(t, "by Jane Doe", fr.trimOwner("by Jane Doe"))
+}
+
+func TestMarketplaceForHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		domain   string
+		currency string
+	}{
+		{"www.amazon.com", "amazon.com", "USD"},
+		{"smile.amazon.com", "amazon.com", "USD"},
+		{"www.amazon.com.br", "amazon.com.br", "BRL"},
+		{"www.amazon.com.au", "amazon.com.au", "AUD"},
+		{"www.amazon.com.mx", "amazon.com.mx", "MXN"},
+		{"www.amazon.com.be", "amazon.com.be", "EUR"},
+		{"www.amazon.com.tr", "amazon.com.tr", "TRY"},
+		{"www.amazon.co.uk", "amazon.co.uk", "GBP"},
+		{"www.amazon.co.jp", "amazon.co.jp", "JPY"},
+		{"WWW.AMAZON.DE", "amazon.de", "EUR"},
+		{"www.amazon.nl", "amazon.nl", "EUR"},
+		{"www.amazon.se", "amazon.se", "SEK"},
+		{"www.amazon.pl", "amazon.pl", "PLN"},
+		{"www.amazon.sg", "amazon.sg", "SGD"},
+		{"www.amazon.ae", "amazon.ae", "AED"},
+		{"www.amazon.sa", "amazon.sa", "SAR"},
+		{"www.amazon.eg", "amazon.eg", "EGP"},
+	}
+
+	// Repeat to catch any dependence on map iteration order.
+	for i := 0; i < 20; i++ {
+		for _, test := range tests {
+			m, ok := MarketplaceForHost(test.host)
+			require.True(t, ok, test.host)
+			require.Equal(t, test.domain, m.Domain, test.host)
+			require.Equal(t, test.currency, m.Currency, test.host)
+		}
+	}
+}
+
+func TestMarketplaceForUnknownHost(t *testing.T) {
+	_, ok := MarketplaceForHost("127.0.0.1")
+	require.False(t, ok)
+
+	_, ok = MarketplaceForHost("notamazon.com")
+	require.False(t, ok)
+
+	require.Equal(t, "amazon.com", marketplaceForHost("127.0.0.1").Domain)
+}
+
+func TestRegisterMarketplace(t *testing.T) {
+	defer func() {
+		marketplacesMu.Lock()
+		defer marketplacesMu.Unlock()
+		unregisterMarketplace("amazon.example")
+	}()
+
+	custom := &Marketplace{
+		Domain:      "www.Amazon.Example",
+		Currency:    "XTS",
+		DateLayouts: []string{"2006-01-02"},
+	}
+	require.NoError(t, RegisterMarketplace(custom))
+	require.Equal(t, "www.Amazon.Example", custom.Domain, "should not change the caller's marketplace")
+
+	m, ok := MarketplaceForHost("www.amazon.example")
+	require.True(t, ok)
+	require.Equal(t, "amazon.example", m.Domain)
+	require.Contains(t, Marketplaces(), m)
+
+	custom.Currency = "XXX"
+	custom.DateLayouts[0] = "02/01/2006"
+	require.Equal(t, "XTS", m.Currency)
+	require.Equal(t, []string{"2006-01-02"}, m.DateLayouts)
+
+	date, err := m.parseDate("2019-07-10")
+	require.NoError(t, err)
+	require.Equal(t, time.July, date.Month())
+
+	replacement := &Marketplace{Domain: "amazon.example", Currency: "XXX"}
+	require.NoError(t, RegisterMarketplace(replacement))
+	replaced, ok := MarketplaceForHost("www.amazon.example")
+	require.True(t, ok)
+	require.Equal(t, "XXX", replaced.Currency)
+	require.NotContains(t, Marketplaces(), m)
+
+	require.Error(t, RegisterMarketplace(&Marketplace{}))
+}
+
+func TestParseDateUnknownTimezone(t *testing.T) {
+	m := &Marketplace{Domain: "amazon.example", Timezone: "Mars/Olympus_Mons",
+		DateLayouts: []string{"2006-01-02"}}
+	_, err := m.parseDate("2019-07-10")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Mars/Olympus_Mons")
+}
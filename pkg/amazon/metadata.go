@@ -0,0 +1,122 @@
+package amazon
+
+import (
+	"strings"
+
+	"github.com/gocolly/colly"
+)
+
+// Privacy describes who can see a wishlist.
+type Privacy int
+
+const (
+	// PrivacyUnknown means the page did not say who can see the wishlist.
+	PrivacyUnknown Privacy = iota
+
+	// PrivacyPublic means anyone can find and see the wishlist.
+	PrivacyPublic
+
+	// PrivacyShared means only people with a link can see the wishlist.
+	PrivacyShared
+
+	// PrivacyPrivate means only the owner can see the wishlist.
+	PrivacyPrivate
+)
+
+const (
+	listNameSelector        = "#profile-list-name"
+	listOwnerSelector       = "#wl-list-owner-name"
+	listDescriptionSelector = "#wl-list-description"
+	listItemCountSelector   = "#wl-list-item-count"
+	listPrivacySelector     = "#wl-list-privacy"
+	listShipToSelector      = "#wl-list-ship-to"
+)
+
+// Metadata describes a wishlist as a whole, rather than the items on it.
+type Metadata struct {
+	// Name is the title the owner gave the wishlist.
+	Name string
+
+	// OwnerName is the name of the person the wishlist belongs to.
+	OwnerName string
+
+	// Description is the "About" text the owner wrote for the wishlist.
+	Description string
+
+	// ItemCount is how many items Amazon says are on the wishlist, or -1 if
+	// unknown.
+	ItemCount int
+
+	// Privacy describes who can see the wishlist.
+	Privacy Privacy
+
+	// HasShippingAddress indicates whether the owner attached a shipping
+	// address, so gifts bought from the wishlist can be sent to them.
+	HasShippingAddress bool
+
+	// ShipTo is how Amazon describes the attached shipping address, e.g.,
+	// "Jane Doe's Gift Registry Address". Amazon does not show the address
+	// itself.
+	ShipTo string
+}
+
+// String returns the name of the privacy setting.
+func (p Privacy) String() string {
+	switch p {
+	case PrivacyPublic:
+		return "public"
+	case PrivacyShared:
+		return "shared"
+	case PrivacyPrivate:
+		return "private"
+	}
+	return "unknown"
+}
+
+// Metadata returns details about this wishlist as a whole, such as who owns
+// it and whether Amazon can ship gifts to them.
+func (w *Wishlist) Metadata() (*Metadata, error) {
+	metadata := &Metadata{ItemCount: -1}
+	c, err := w.collector()
+	if err != nil {
+		return nil, err
+	}
+
+	w.onMetadata(c, metadata)
+
+	if err := w.loadWishlist(c); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// onMetadata registers the callbacks that read the wishlist's header into
+// metadata.
+func (w *Wishlist) onMetadata(c *colly.Collector, metadata *Metadata) {
+	c.OnHTML(w.nameSelector(), func(el *colly.HTMLElement) {
+		w.onName(el)
+		metadata.Name = w.name
+	})
+	c.OnHTML(listOwnerSelector, func(el *colly.HTMLElement) {
+		metadata.OwnerName = w.marketplace.trimOwner(el.Text)
+	})
+	c.OnHTML(listDescriptionSelector, func(el *colly.HTMLElement) {
+		metadata.Description = strings.TrimSpace(el.Text)
+	})
+	c.OnHTML(listItemCountSelector, func(el *colly.HTMLElement) {
+		count, err := parseCount(el.Text)
+		if err != nil {
+			w.errors = append(w.errors, err)
+			return
+		}
+		metadata.ItemCount = count
+	})
+	c.OnHTML(listPrivacySelector, func(el *colly.HTMLElement) {
+		metadata.Privacy = w.marketplace.parsePrivacy(el.Text)
+	})
+	c.OnHTML(listShipToSelector, func(el *colly.HTMLElement) {
+		metadata.ShipTo = w.marketplace.trimShipTo(el.Text)
+		metadata.HasShippingAddress = len(metadata.ShipTo) > 0
+	})
+}
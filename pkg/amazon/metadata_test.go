@@ -0,0 +1,110 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata(t *testing.T) {
+	id := "2F4QUD8ZV4XOE"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(metadataHTML))
+	}))
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	metadata, err := wishlist.Metadata()
+	require.NoError(t, err)
+	require.Equal(t, "Cat Shelter Supplies", metadata.Name)
+	require.Equal(t, "Jane Doe", metadata.OwnerName)
+	require.Equal(t, "Supplies for the cats at the shelter.", metadata.Description)
+	require.Equal(t, 1, metadata.ItemCount)
+	require.Equal(t, PrivacyPublic, metadata.Privacy)
+	require.True(t, metadata.HasShippingAddress)
+	require.Equal(t, "Jane Doe's Gift Registry Address", metadata.ShipTo)
+}
+
+func TestMetadataMissing(t *testing.T) {
+	id := "3I6EQPZ8OB1DT"
+	ts := newTestServer(t, id)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	metadata, err := wishlist.Metadata()
+	require.NoError(t, err)
+	require.Equal(t, "NHA Wish List", metadata.Name)
+	require.Equal(t, "", metadata.OwnerName)
+	require.Equal(t, "", metadata.Description)
+	require.Equal(t, -1, metadata.ItemCount)
+	require.Equal(t, PrivacyUnknown, metadata.Privacy)
+	require.False(t, metadata.HasShippingAddress)
+}
+
+func TestSnapshotMetadata(t *testing.T) {
+	id := "2F4QUD8ZV4XOE"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(metadataHTML))
+	}))
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	snapshot, err := wishlist.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, "Jane Doe", snapshot.Metadata.OwnerName)
+	require.Equal(t, PrivacyPublic, snapshot.Metadata.Privacy)
+	require.Len(t, snapshot.Items, 1)
+}
+
+// metadataHTML is the header of a second wishlist, one with an owner,
+// description, and gift address, which the recorded wishlistHTML lacks.
+const metadataHTML = `<!doctype html>
+<html>
+	<body>
+		<div id="wl-list-info" class="a-row">
+			<a id="wl-print-link" data-reg-nav-link="{&quot;newTab&quot;:1}" class="a-link-normal a-declarative" href="/hz/wishlist/printview/2F4QUD8ZV4XOE">Print List</a>
+			<span id="profile-list-name" aria-level="2" class="a-size-medium a-text-bold" role="heading">Cat Shelter Supplies</span>
+			<div class="a-row a-spacing-mini">
+				<span id="wl-list-owner-name" class="a-size-base a-color-secondary">by
+					Jane Doe</span>
+				<i class="a-icon a-icon-text-separator" role="img"></i>
+				<span id="wl-list-privacy" class="a-size-small a-color-secondary">Public</span>
+				<i class="a-icon a-icon-text-separator" role="img"></i>
+				<span id="wl-list-item-count" class="a-size-small a-color-secondary">1 item</span>
+			</div>
+			<div id="wl-list-description" class="a-row a-spacing-small">
+				Supplies for the cats at the shelter.
+			</div>
+			<div class="a-row a-spacing-small">
+				<i class="a-icon a-icon-addon-location" role="img"></i>
+				<span id="wl-list-ship-to" class="a-size-small">Ship to: Jane Doe's Gift Registry Address</span>
+			</div>
+		</div>
+		<ul id="g-items" class="a-unordered-list a-nostyle a-vertical a-spacing-none g-items-section ui-sortable">
+			<li data-id="2F4QUD8ZV4XOE" data-itemId="I1Q9WFOWXFX4MT" data-price="8.99" class="a-spacing-none g-item-sortable">
+				<span class="a-list-item">
+					<div id="item_I1Q9WFOWXFX4MT" class="a-section">
+						<h3 class="a-size-base"><a id="itemName_I1Q9WFOWXFX4MT" class="a-link-normal" title="KONG Cat Wubba Catnip Toy" href="/dp/B000IYSAIW/?coliid=I1Q9WFOWXFX4MT&amp;colid=2F4QUD8ZV4XOE&amp;psc=1">KONG Cat Wubba Catnip Toy</a></h3>
+						<span id="itemPrice_I1Q9WFOWXFX4MT" class="a-price" data-a-size="m" data-a-color="base"><span class="a-offscreen">$8.99</span></span>
+						<div class="dateAddedText">
+							<span id="itemAddedDate_I1Q9WFOWXFX4MT" class="a-size-small">Added March 2, 2020</span>
+						</div>
+					</div>
+				</span>
+			</li>
+		</ul>
+	</body>
+</html>`
@@ -0,0 +1,82 @@
+package amazon
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// priceRegexp matches the number in a price, with any thousands and
+	// decimal separators, e.g., "1,234.56", "1.234,56", or "1 234,56".
+	priceRegexp = regexp.MustCompile(`\d[\d.,\s\x{00a0}\x{202f}]*`)
+)
+
+// Price is an amount of money in a particular currency.
+type Price struct {
+	// Amount is the number of units of the currency, e.g., 15.96.
+	Amount float64 `json:"amount"`
+
+	// Currency is the ISO 4217 code of the currency, e.g., "USD".
+	Currency string `json:"currency"`
+}
+
+// String returns the amount and currency, e.g., "15.96 USD".
+func (p *Price) String() string {
+	return fmt.Sprintf("%.2f %s", p.Amount, p.Currency)
+}
+
+// ParsedPrice returns the price of this product as a number, in the currency
+// of the Amazon marketplace the wishlist is on.
+func (i *Item) ParsedPrice() (*Price, error) {
+	if i.Price == "" {
+		return nil, fmt.Errorf("No price found for item %s", i.ID)
+	}
+
+	marketplace := i.marketplace
+	if marketplace == nil {
+		marketplace = defaultMarketplace()
+	}
+
+	return parsePrice(i.Price, marketplace)
+}
+
+// parsePrice reads a localized price such as "$1,234.56", "1.234,56 €", or
+// "￥1,234". Marketplaces disagree on separators, so a separator followed by
+// exactly one or two digits at the end is taken as the decimal point and the
+// others as thousands separators.
+func parsePrice(text string, m *Marketplace) (*Price, error) {
+	match := strings.TrimSpace(priceRegexp.FindString(text))
+	if match == "" {
+		return nil, fmt.Errorf("No price found in '%s'", strings.TrimSpace(text))
+	}
+
+	match = strings.TrimRight(match, ".,")
+	digits := match
+	decimals := ""
+	if separator := strings.LastIndexAny(match, ".,"); separator > -1 {
+		fraction := match[separator+1:]
+		if len(fraction) > 0 && len(fraction) <= 2 && nonDigitRegexp.FindString(fraction) == "" {
+			digits = match[:separator]
+			decimals = fraction
+		}
+	}
+
+	digits = nonDigitRegexp.ReplaceAllString(digits, "")
+	if decimals != "" {
+		digits += "." + decimals
+	}
+
+	amount, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := DefaultCurrency
+	if m != nil && m.Currency != "" {
+		currency = m.Currency
+	}
+
+	return &Price{Amount: amount, Currency: currency}, nil
+}
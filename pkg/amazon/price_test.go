@@ -0,0 +1,49 @@
+package amazon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		host     string
+		text     string
+		amount   float64
+		currency string
+	}{
+		{"www.amazon.com", "$15.96", 15.96, "USD"},
+		{"www.amazon.com", "$1,234.56", 1234.56, "USD"},
+		{"www.amazon.com", "$1,234", 1234, "USD"},
+		{"www.amazon.com", " $32.99 - $45.00 ", 32.99, "USD"},
+		{"www.amazon.de", "15,96 €", 15.96, "EUR"},
+		{"www.amazon.de", "1.234,56 €", 1234.56, "EUR"},
+		{"www.amazon.fr", "1 234,56 €", 1234.56, "EUR"},
+		{"www.amazon.co.jp", "￥1,234", 1234, "JPY"},
+		{"www.amazon.com.br", "R$ 1.234,5", 1234.5, "BRL"},
+		{"www.amazon.co.uk", "£7.", 7, "GBP"},
+	}
+
+	for _, test := range tests {
+		price, err := parsePrice(test.text, marketplaceForHost(test.host))
+		require.NoError(t, err, test.text)
+		require.InDelta(t, test.amount, price.Amount, 0.001, test.text)
+		require.Equal(t, test.currency, price.Currency, test.text)
+	}
+
+	_, err := parsePrice("Price unavailable", marketplaceForHost("www.amazon.com"))
+	require.Error(t, err)
+}
+
+func TestItemParsedPrice(t *testing.T) {
+	item := NewItem("I2G6UJO0FYWV8J", "Cat Litter", "https://www.amazon.com/dp/B0018CLTKE")
+	_, err := item.ParsedPrice()
+	require.Error(t, err)
+
+	item.Price = "$15.96"
+	price, err := item.ParsedPrice()
+	require.NoError(t, err)
+	require.Equal(t, &Price{Amount: 15.96, Currency: "USD"}, price)
+	require.Equal(t, "15.96 USD", price.String())
+}
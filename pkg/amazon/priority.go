@@ -0,0 +1,53 @@
+package amazon
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Priority is how much the owner of a wishlist wants an item on it, using
+// the same numbers Amazon does.
+type Priority int
+
+const (
+	// PriorityLowest is the lowest priority an item can have.
+	PriorityLowest Priority = -2
+
+	// PriorityLow is below the default priority.
+	PriorityLow Priority = -1
+
+	// PriorityMedium is the default priority, which items on lists without
+	// priorities, such as registries, also have.
+	PriorityMedium Priority = 0
+
+	// PriorityHigh is above the default priority.
+	PriorityHigh Priority = 1
+
+	// PriorityHighest is the highest priority an item can have.
+	PriorityHighest Priority = 2
+)
+
+// String returns the name Amazon uses for the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLowest:
+		return "lowest"
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	case PriorityHighest:
+		return "highest"
+	}
+	return "medium"
+}
+
+// parsePriority reads the number Amazon stores an item's priority as, which
+// unlike the label shown next to it is the same in every language.
+func parsePriority(text string) (Priority, bool) {
+	value, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || value < int(PriorityLowest) || value > int(PriorityHighest) {
+		return PriorityMedium, false
+	}
+	return Priority(value), true
+}
@@ -0,0 +1,42 @@
+package amazon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := map[string]Priority{
+		"-2":  PriorityLowest,
+		"-1":  PriorityLow,
+		" 0 ": PriorityMedium,
+		"1":   PriorityHigh,
+		"2":   PriorityHighest,
+	}
+
+	for text, expected := range tests {
+		priority, ok := parsePriority(text)
+		require.True(t, ok, text)
+		require.Equal(t, expected, priority, text)
+	}
+
+	for _, text := range []string{"", "3", "medium"} {
+		_, ok := parsePriority(text)
+		require.False(t, ok, text)
+	}
+}
+
+func TestOnPrioritySpan(t *testing.T) {
+	span := newTestHTMLElement(t, `<span id="itemPriority_I2G6UJO0FYWV8J" class="a-hidden">1</span>`)
+
+	wishlist, err := NewWishlistFromID("3I6EQPZ8OB1DT")
+	require.NoError(t, err)
+	wishlist.addItem("I2G6UJO0FYWV8J", "Cat Litter", "https://www.amazon.com/dp/B0018CLTKE")
+
+	wishlist.onPrioritySpan("I2G6UJO0FYWV8J", span)
+	require.Equal(t, PriorityHigh, wishlist.items["I2G6UJO0FYWV8J"].Priority)
+
+	wishlist.onPrioritySpan("unknown", span)
+	require.Len(t, wishlist.items, 1, "should ignore spans of unknown items")
+}
@@ -0,0 +1,305 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gocolly/colly"
+)
+
+const (
+	// listNavLinkSelector matches the links in the list switcher shown next to
+	// a wishlist and on a public profile's lists tab.
+	listNavLinkSelector = "#left-nav a[href], #profile-lists a[href]"
+
+	listEntryTitleIDPrefix = "wl-list-entry-title-"
+	profileIDPrefix        = "amzn1.account."
+)
+
+var (
+	profilePathRegexp = regexp.MustCompile(`^/(?:gp/)?profile/(amzn1\.account\.[A-Za-z0-9]+)(?:/.*)?$`)
+	listsPathRegexp   = regexp.MustCompile(`^/hz/wishlist/ls/?$`)
+)
+
+// Profile is a page that links to an Amazon user's lists: either their public
+// profile, or the "Your Lists" page at "/hz/wishlist/ls", which shows the
+// lists of whoever is signed in.
+type Profile struct {
+	// ID is the profile's identifier, empty for the "Your Lists" page.
+	// Example: "amzn1.account.ABC123"
+	ID string
+
+	// URL is the page that links to the lists.
+	URL string
+
+	baseURL string
+}
+
+// NewProfileFromID constructs the public profile with the given ID on
+// DefaultAmazonDomain.
+func NewProfileFromID(id string) (*Profile, error) {
+	return NewProfileFromIDAtDomain(id, DefaultAmazonDomain)
+}
+
+// NewProfileFromIDAtDomain constructs the public profile with the given ID on
+// the Amazon site at amazonDomain, e.g., "https://www.amazon.co.uk".
+func NewProfileFromIDAtDomain(id string, amazonDomain string) (*Profile, error) {
+	if !strings.HasPrefix(id, profileIDPrefix) {
+		return nil, fmt.Errorf("'%s' is not an Amazon profile ID", id)
+	}
+
+	baseURL, err := baseURLFromURL(amazonDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{
+		ID:      id,
+		URL:     fmt.Sprintf("%s/gp/profile/%s", baseURL, url.PathEscape(id)),
+		baseURL: baseURL,
+	}, nil
+}
+
+// ParseProfileURL parses a link to an Amazon user's public profile, such as
+// "https://www.amazon.com/gp/profile/amzn1.account.ABC123", or to the "Your
+// Lists" page, "https://www.amazon.com/hz/wishlist/ls". Links to a list, even
+// "https://www.amazon.com/hz/wishlist/ls?lid=3I6EQPZ8OB1DT", are an error.
+func ParseProfileURL(urlStr string) (*Profile, error) {
+	uri, err := url.Parse(strings.TrimSpace(urlStr))
+	if err != nil {
+		return nil, err
+	}
+	if !uri.IsAbs() || uri.Host == "" {
+		return nil, fmt.Errorf("URL '%s' is not an absolute URL to an Amazon profile",
+			urlStr)
+	}
+
+	baseURL := fmt.Sprintf("%s://%s", uri.Scheme, uri.Host)
+	if m, ok := MarketplaceForHost(uri.Hostname()); ok {
+		baseURL = m.URL()
+	}
+
+	if listsPathRegexp.MatchString(uri.EscapedPath()) {
+		if uri.Query().Get("lid") != "" {
+			// "/hz/wishlist/ls?lid=..." is a link to one list, not to the
+			// "Your Lists" page.
+			return nil, fmt.Errorf("URL '%s' is a link to an Amazon list, not a profile",
+				urlStr)
+		}
+		return &Profile{URL: baseURL + "/hz/wishlist/ls", baseURL: baseURL}, nil
+	}
+
+	matches := profilePathRegexp.FindStringSubmatch(uri.EscapedPath())
+	if matches == nil {
+		return nil, fmt.Errorf("URL '%s' is not a link to an Amazon profile", urlStr)
+	}
+
+	return NewProfileFromIDAtDomain(matches[1], baseURL)
+}
+
+// ListsForProfile returns the public lists of an Amazon user, given either a
+// link to one of their lists, a link to a Profile, or a profile or list ID on
+// DefaultAmazonDomain. The returned wishlists have their names loaded. Pages
+// are loaded with the default settings, so the "Your Lists" page, which needs
+// a signed-in session, can't be loaded this way; use Profile.ListsUsing.
+func ListsForProfile(profileURLOrID string) ([]*Wishlist, error) {
+	profileURLOrID = strings.TrimSpace(profileURLOrID)
+	if len(profileURLOrID) < 1 {
+		return nil, errors.New("No Amazon profile URL or ID provided")
+	}
+
+	if strings.HasPrefix(profileURLOrID, profileIDPrefix) {
+		profile, err := NewProfileFromID(profileURLOrID)
+		if err != nil {
+			return nil, err
+		}
+		return profile.Lists()
+	}
+
+	if !strings.Contains(profileURLOrID, "/") {
+		w, err := NewWishlistFromID(profileURLOrID)
+		if err != nil {
+			return nil, err
+		}
+		return w.Lists()
+	}
+
+	if ref, err := ParseWishlistURL(profileURLOrID); err == nil {
+		w, err := NewWishlistFromRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		return w.Lists()
+	}
+
+	profile, err := ParseProfileURL(profileURLOrID)
+	if err != nil {
+		return nil, fmt.Errorf("URL '%s' is not a link to an Amazon list or profile",
+			profileURLOrID)
+	}
+	return profile.Lists()
+}
+
+// Lists returns the public lists the profile links to. The returned wishlists
+// have their names loaded and use the default settings; use the lists' setters
+// to change them before loading items.
+func (p *Profile) Lists() ([]*Wishlist, error) {
+	return p.ListsUsing(nil)
+}
+
+// ListsUsing returns the public lists the profile links to, loading the
+// profile with the settings of the given wishlist, such as its cookie jar,
+// proxies, browser profile, and captcha solver. The "Your Lists" page needs
+// a cookie jar with a signed-in session; without one, ErrSignInRequired is
+// returned. The returned wishlists have their names loaded and share the
+// given wishlist's settings.
+func (p *Profile) ListsUsing(settings *Wishlist) ([]*Wishlist, error) {
+	uri, err := url.Parse(p.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The profile is loaded with the same machinery as a wishlist, but has
+	// no wishlist of its own.
+	loader := &Wishlist{
+		CacheResults: true,
+		urls:         []string{p.URL},
+		items:        map[string]*Item{},
+		baseURL:      p.baseURL,
+		marketplace:  marketplaceForHost(uri.Hostname()),
+		errors:       []error{},
+	}
+	if settings != nil {
+		loader.useSettingsOf(settings)
+	}
+
+	// The "Your Lists" page shows the owner's private and shared lists too,
+	// so only the lists it labels public are returned from it.
+	return loader.lists(p.ID == "")
+}
+
+// Lists returns the public lists shown in the list switcher next to this
+// wishlist, which are the owner's other public lists along with this one.
+// Lists the switcher labels as private or shared are skipped. The returned
+// wishlists have their names loaded and share this wishlist's settings.
+func (w *Wishlist) Lists() ([]*Wishlist, error) {
+	return w.lists(false)
+}
+
+// lists returns the lists linked to from the page the wishlist loads. If
+// labeledPublic is true, only lists labeled public are returned, rather than
+// any not labeled private or shared.
+func (w *Wishlist) lists(labeledPublic bool) ([]*Wishlist, error) {
+	lists := []*Wishlist{}
+	seen := map[string]bool{}
+	c, err := w.collector()
+	if err != nil {
+		return nil, err
+	}
+
+	c.OnHTML(listNavLinkSelector, func(link *colly.HTMLElement) {
+		list := w.onListNavLink(link, labeledPublic)
+		if list == nil || seen[list.id] {
+			return
+		}
+		seen[list.id] = true
+		lists = append(lists, list)
+	})
+
+	if err := w.loadWishlist(c); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (w *Wishlist) onListNavLink(link *colly.HTMLElement, labeledPublic bool) *Wishlist {
+	relativeURL := link.Attr("href")
+	if len(relativeURL) < 1 {
+		return nil
+	}
+
+	ref, err := ParseWishlistURL(link.Request.AbsoluteURL(relativeURL))
+	if err != nil {
+		return nil
+	}
+
+	privacy := listNavLinkPrivacy(w.marketplace, ref.ID, link)
+	if privacy == PrivacyPrivate || privacy == PrivacyShared ||
+		(labeledPublic && privacy != PrivacyPublic) {
+		return nil
+	}
+
+	list, err := NewWishlistFromRef(ref)
+	if err != nil {
+		w.errors = append(w.errors, err)
+		return nil
+	}
+
+	list.useSettingsOf(w)
+	list.name = listNavLinkName(ref.ID, link)
+
+	return list
+}
+
+// useSettingsOf makes the wishlist load pages the way the given one does,
+// sharing its cookies, proxies, and browser profile.
+func (w *Wishlist) useSettingsOf(other *Wishlist) {
+	w.DebugMode = other.DebugMode
+	w.CacheResults = other.CacheResults
+	w.AssociateTag = other.AssociateTag
+	w.Layouts = other.Layouts
+	w.proxyPool = other.proxyPool
+	w.cookieJar = other.cookieJar
+	w.sessionJar = other.sessionJar
+	w.Browser = other.Browser
+	w.UserAgent = other.UserAgent
+	w.CaptchaSolver = other.CaptchaSolver
+}
+
+// listNavLinkName returns the name of the list a link in the list switcher
+// points to, preferring the element Amazon uses for the list's title over the
+// whole link, which also says whether the list is public or private.
+func listNavLinkName(id string, link *colly.HTMLElement) string {
+	if name := collapseSpace(link.ChildText("#" + listEntryTitleIDPrefix + id)); name != "" {
+		return name
+	}
+	if name := collapseSpace(link.Attr("title")); name != "" {
+		return name
+	}
+	return collapseSpace(link.Text)
+}
+
+// listNavLinkPrivacy returns who can see the list with the given ID a link in
+// the list switcher points to, going by the marketplace's label in the element
+// next to the list's title element. Links without a title element are judged
+// by their text, less the title attribute if there is one; private and shared
+// labels win over a public one that is part of the list's name. Links without
+// a label, like those on a public profile, are PrivacyUnknown.
+func listNavLinkPrivacy(m *Marketplace, id string, link *colly.HTMLElement) Privacy {
+	titleID := listEntryTitleIDPrefix + id
+	if link.DOM.Find("#"+titleID).Length() < 1 {
+		label := collapseSpace(link.Text)
+		if title := collapseSpace(link.Attr("title")); title != "" {
+			label = strings.Replace(label, title, "", 1)
+		}
+		return m.parsePrivacy(label)
+	}
+
+	label := ""
+	link.ForEachWithBreak("span", func(index int, span *colly.HTMLElement) bool {
+		if span.Attr("id") == titleID {
+			return true
+		}
+		label = collapseSpace(span.Text)
+		return label == ""
+	})
+	return m.parsePrivacy(label)
+}
+
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,220 @@
+package amazon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListsForProfileFromWishlistURL(t *testing.T) {
+	ts := newProfileTestServer(t)
+	defer ts.Close()
+
+	lists, err := ListsForProfile(ts.URL + "/hz/wishlist/ls/123abc?ref_=wl_share")
+	require.NoError(t, err)
+	require.Len(t, lists, 2, "should skip the shared and private lists")
+
+	require.Equal(t, "123abc", lists[0].ID())
+	require.Equal(t, "456def", lists[1].ID())
+
+	name, err := lists[0].Name()
+	require.NoError(t, err)
+	require.Equal(t, "NHA Wish List", name)
+	name, err = lists[1].Name()
+	require.NoError(t, err)
+	require.Equal(t, "Books", name)
+
+	require.Contains(t, lists[1].URLs()[0], ts.URL+"/hz/wishlist/ls/456def")
+}
+
+func TestListsForProfileFromListIDQueryURL(t *testing.T) {
+	clearCache(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hz/wishlist/ls/123abc", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(listNavHTML))
+	})
+	mux.HandleFunc("/hz/wishlist/ls", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should load the list, not the Your Lists page")
+		http.NotFound(w, r)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	lists, err := ListsForProfile(ts.URL + "/hz/wishlist/ls?lid=123abc")
+	require.NoError(t, err)
+	require.Len(t, lists, 2)
+	require.Equal(t, "123abc", lists[0].ID())
+}
+
+func TestListsForProfileFromProfileURL(t *testing.T) {
+	ts := newProfileTestServer(t)
+	defer ts.Close()
+
+	lists, err := ListsForProfile(ts.URL + "/gp/profile/amzn1.account.ABC123XYZ")
+	require.NoError(t, err)
+	require.Len(t, lists, 2)
+
+	require.Equal(t, "123abc", lists[0].ID())
+	name, err := lists[0].Name()
+	require.NoError(t, err)
+	require.Equal(t, "NHA Wish List", name)
+
+	require.Equal(t, "456def", lists[1].ID())
+	name, err = lists[1].Name()
+	require.NoError(t, err)
+	require.Equal(t, "Books", name)
+}
+
+func TestListsForProfileFromYourListsURL(t *testing.T) {
+	ts := newProfileTestServer(t)
+	defer ts.Close()
+
+	_, err := ListsForProfile(ts.URL + "/hz/wishlist/ls")
+	require.Equal(t, ErrSignInRequired, err)
+}
+
+func TestProfileListsUsingCookieJar(t *testing.T) {
+	ts := newProfileTestServer(t)
+	defer ts.Close()
+
+	uri, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	jar, err := ReadCookies(strings.NewReader(fmt.Sprintf(
+		"# Netscape HTTP Cookie File\n%s\tFALSE\t/\tFALSE\t0\tsession-id\t123-4567890\n",
+		uri.Hostname())))
+	require.NoError(t, err)
+
+	settings, err := NewWishlistFromIDAtDomain("123abc", ts.URL)
+	require.NoError(t, err)
+	settings.CacheResults = false
+	settings.SetCookieJar(jar)
+
+	profile, err := ParseProfileURL(ts.URL + "/hz/wishlist/ls")
+	require.NoError(t, err)
+	lists, err := profile.ListsUsing(settings)
+	require.NoError(t, err)
+	require.Len(t, lists, 2, "should skip lists not labeled public")
+	require.Equal(t, "123abc", lists[0].ID())
+	require.Equal(t, "456def", lists[1].ID())
+	require.Equal(t, jar, lists[0].cookieJar)
+	require.False(t, lists[0].CacheResults)
+}
+
+func TestParseProfileURL(t *testing.T) {
+	profile, err := ParseProfileURL("https://smile.amazon.com/gp/profile/amzn1.account.ABC123XYZ/ref=cm_cr_dp_d_gw_tr")
+	require.NoError(t, err)
+	require.Equal(t, "amzn1.account.ABC123XYZ", profile.ID)
+	require.Equal(t, "https://www.amazon.com/gp/profile/amzn1.account.ABC123XYZ", profile.URL)
+
+	profile, err = ParseProfileURL("https://www.amazon.co.uk/hz/wishlist/ls/?ref_=nav_wishlist")
+	require.NoError(t, err)
+	require.Equal(t, "", profile.ID)
+	require.Equal(t, "https://www.amazon.co.uk/hz/wishlist/ls", profile.URL)
+
+	_, err = ParseProfileURL("https://www.amazon.com/hz/wishlist/ls/123abc")
+	require.Error(t, err)
+	_, err = ParseProfileURL("https://www.amazon.com/hz/wishlist/ls?lid=3I6EQPZ8OB1DT")
+	require.Error(t, err)
+	_, err = NewProfileFromID("123abc")
+	require.Error(t, err)
+}
+
+func TestListsForProfileErrors(t *testing.T) {
+	_, err := ListsForProfile("")
+	require.Error(t, err)
+
+	_, err = ListsForProfile("https://www.amazon.com/dp/B0018CLTKE")
+	require.Error(t, err)
+}
+
+const listNavHTML = `<!doctype html>
+<html>
+	<body>
+		<div id="left-nav" class="a-section">
+			<a id="wl-list-link-123abc" class="a-link-normal" href="/hz/wishlist/ls/123abc?ref_=wl_list_1">
+				<span id="wl-list-entry-title-123abc" class="wl-list-entry-title">NHA Wish List</span>
+				<span class="a-size-small">Public</span>
+			</a>
+			<a id="wl-list-link-456def" class="a-link-normal" href="/hz/wishlist/ls/456def?ref_=wl_list_2">
+				<span id="wl-list-entry-title-456def" class="wl-list-entry-title">Books</span>
+				<span class="a-size-small">Public</span>
+			</a>
+			<a id="wl-list-link-789ghi" class="a-link-normal" title="Kitchen" href="/hz/wishlist/ls/789ghi">Kitchen &middot; Shared</a>
+			<a id="wl-list-link-321cba" class="a-link-normal" href="/hz/wishlist/ls/321cba">Secret Santa &middot; Private</a>
+			<a class="a-link-normal" href="/hz/wishlist/intro">Create a List</a>
+		</div>
+		<span id="profile-list-name">NHA Wish List</span>
+		<a id="wl-print-link" href="/hz/wishlist/printview/123abc">Print List</a>
+		<ul id="g-items"></ul>
+	</body>
+</html>`
+
+// yourListsHTML is the "Your Lists" page, which also shows lists with no
+// label saying who can see them.
+const yourListsHTML = `<!doctype html>
+<html>
+	<body>
+		<div id="left-nav" class="a-section">
+			<a id="wl-list-link-123abc" class="a-link-normal" href="/hz/wishlist/ls/123abc?ref_=wl_list_1">
+				<span id="wl-list-entry-title-123abc" class="wl-list-entry-title">NHA Wish List</span>
+				<span class="a-size-small">Public</span>
+			</a>
+			<a id="wl-list-link-456def" class="a-link-normal" href="/hz/wishlist/ls/456def?ref_=wl_list_2">
+				<span id="wl-list-entry-title-456def" class="wl-list-entry-title">Books</span>
+				<span class="a-size-small">Public</span>
+			</a>
+			<a id="wl-list-link-654fed" class="a-link-normal" href="/hz/wishlist/ls/654fed?ref_=wl_list_3">
+				<span id="wl-list-entry-title-654fed" class="wl-list-entry-title">Drafts</span>
+			</a>
+			<a id="wl-list-link-321cba" class="a-link-normal" href="/hz/wishlist/ls/321cba">Secret Santa &middot; Private</a>
+		</div>
+	</body>
+</html>`
+
+const profileHTML = `<!doctype html>
+<html>
+	<body>
+		<h1>Jane Doe</h1>
+		<div id="profile-lists" class="a-section">
+			<a class="a-link-normal" href="/hz/wishlist/ls/123abc"><span id="wl-list-entry-title-123abc">NHA Wish List</span></a>
+			<a class="a-link-normal" href="/hz/wishlist/ls/456def"><span id="wl-list-entry-title-456def">Books</span></a>
+			<a class="a-link-normal" href="/hz/wishlist/ls/123abc">See list</a>
+		</div>
+		<a href="/hz/wishlist/ls/999zzz">Someone else's list</a>
+	</body>
+</html>`
+
+func newProfileTestServer(t *testing.T) *httptest.Server {
+	clearCache(t)
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/hz/wishlist/ls/123abc", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(listNavHTML))
+	})
+	mux.HandleFunc("/hz/wishlist/ls", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session-id"); err != nil {
+			http.Redirect(w, r, "/ap/signin?openid.return_to="+url.QueryEscape(r.URL.String()),
+				http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(yourListsHTML))
+	})
+	mux.HandleFunc("/ap/signin", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<form name="signIn" method="post"><input type="email" name="email"></form>`))
+	})
+	mux.HandleFunc("/gp/profile/amzn1.account.ABC123XYZ", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(profileHTML))
+	})
+
+	return httptest.NewServer(mux)
+}
@@ -0,0 +1,346 @@
+package amazon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultProxyMaxFailures is how many times in a row a proxy can fail
+	// before it is benched, unless the pool says otherwise.
+	DefaultProxyMaxFailures = 3
+
+	// DefaultProxyBenchDuration is how long a failing proxy is benched,
+	// unless the pool says otherwise.
+	DefaultProxyBenchDuration = 5 * time.Minute
+)
+
+// ProxyPool rotates requests through a set of proxies, keeping track of how
+// each one is doing. Proxies that fail several times in a row, whether with
+// network errors, throttling, or Amazon's robot check, are benched for a while
+// and healthy proxies are preferred. A pool can be shared by many wishlists.
+type ProxyPool struct {
+	// MaxFailures is how many times in a row a proxy can fail before it is
+	// benched. If zero, DefaultProxyMaxFailures is used.
+	MaxFailures int
+
+	// BenchDuration is how long a proxy is skipped after being benched. If
+	// zero, DefaultProxyBenchDuration is used.
+	BenchDuration time.Duration
+
+	mutex         sync.Mutex
+	proxies       []*pooledProxy
+	next          int
+	now           func() time.Time
+	transportOnce sync.Once
+	roundTripper  http.RoundTripper
+}
+
+// ProxyStats describes how a proxy in a pool has been doing.
+type ProxyStats struct {
+	// URL is the proxy's URL, with any password redacted.
+	URL string
+
+	// Requests is how many requests have been sent through the proxy.
+	Requests int
+
+	// Successes is how many requests through the proxy got a usable response
+	// from Amazon, i.e., one that was not throttled or a robot check.
+	Successes int
+
+	// Errors is how many requests through the proxy failed outright or were
+	// throttled.
+	Errors int
+
+	// RobotChecks is how many responses through the proxy were Amazon's
+	// "are you a robot?" page.
+	RobotChecks int
+
+	// ConsecutiveFailures is how many of the latest requests through the proxy
+	// failed in a row.
+	ConsecutiveFailures int
+
+	// AverageLatency is the mean time taken for Amazon to respond through the
+	// proxy.
+	AverageLatency time.Duration
+
+	// LastError is the most recent failure through the proxy, if any.
+	LastError string
+
+	// BenchedUntil is when the proxy will be used again, if it is benched.
+	BenchedUntil time.Time
+
+	// Benched is true if the proxy is currently being skipped.
+	Benched bool
+}
+
+type pooledProxy struct {
+	uri          *url.URL
+	stats        ProxyStats
+	responses    int
+	totalLatency time.Duration
+}
+
+// pooledProxyKey is the context key for the proxy picked for a request.
+type pooledProxyKey struct{}
+
+type proxyPoolTransport struct {
+	pool      *ProxyPool
+	transport http.RoundTripper
+}
+
+// NewProxyPool returns a pool of the proxies at the given URLs. See
+// Wishlist.SetProxyURLs for the kinds of URLs supported.
+func NewProxyPool(urls ...string) (*ProxyPool, error) {
+	if len(urls) < 1 {
+		return nil, errors.New("No proxy URLs provided")
+	}
+
+	pool := &ProxyPool{now: time.Now}
+	seen := map[string]bool{}
+	for _, rawURL := range urls {
+		uri, err := parseProxyURL(rawURL)
+		if err != nil {
+			return nil, err
+		}
+
+		key := transportProxyURL(uri).String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		proxy := &pooledProxy{
+			uri:   uri,
+			stats: ProxyStats{URL: redactProxyURL(uri)},
+		}
+		pool.proxies = append(pool.proxies, proxy)
+	}
+
+	return pool, nil
+}
+
+// Stats returns how each proxy in the pool has been doing, in the order the
+// proxies were given.
+func (p *ProxyPool) Stats() []ProxyStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	stats := make([]ProxyStats, len(p.proxies))
+	for i, proxy := range p.proxies {
+		stats[i] = proxy.stats
+		stats[i].Benched = proxy.isBenched(now)
+	}
+	return stats
+}
+
+// String returns a summary of each proxy's stats, one per line.
+func (p *ProxyPool) String() string {
+	stats := p.Stats()
+	lines := make([]string, len(stats))
+	for i, proxyStats := range stats {
+		lines[i] = proxyStats.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String returns a one-line summary of the proxy's stats.
+func (s ProxyStats) String() string {
+	summary := fmt.Sprintf("%s: %d request(s), %d success(es), %d error(s), %d robot check(s), %s average latency",
+		s.URL, s.Requests, s.Successes, s.Errors, s.RobotChecks, s.AverageLatency)
+	if s.Benched {
+		summary += fmt.Sprintf(", benched until %s", s.BenchedUntil.Format(time.RFC3339))
+	}
+	return summary
+}
+
+// pick returns the unbenched proxy with the lowest failure rate, rotating
+// among equally healthy ones. When every proxy is benched, the one that will
+// be back soonest is used rather than failing outright.
+func (p *ProxyPool) pick() *pooledProxy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	var best *pooledProxy
+	bestIndex := 0
+	for i := 0; i < len(p.proxies); i++ {
+		index := (p.next + i) % len(p.proxies)
+		proxy := p.proxies[index]
+		if proxy.isBenched(now) {
+			continue
+		}
+		if best == nil || proxy.failureRate() < best.failureRate() {
+			best = proxy
+			bestIndex = index
+		}
+	}
+
+	if best == nil {
+		for index, proxy := range p.proxies {
+			if best == nil || proxy.stats.BenchedUntil.Before(best.stats.BenchedUntil) {
+				best = proxy
+				bestIndex = index
+			}
+		}
+	}
+
+	p.next = bestIndex + 1
+	best.stats.Requests++
+	return best
+}
+
+func (p *ProxyPool) recordResponse(proxy *pooledProxy, latency time.Duration, statusCode int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.recordLatency(proxy, latency)
+
+	if isProxyFailureStatus(statusCode) {
+		proxy.stats.Errors++
+		p.recordFailure(proxy, fmt.Sprintf("Status %d", statusCode))
+		return
+	}
+
+	proxy.stats.Successes++
+	proxy.stats.ConsecutiveFailures = 0
+}
+
+// isProxyFailureStatus returns true if a response with the given status counts
+// against the proxy that fetched it: the proxy rejected its credentials, Amazon
+// throttled it, or the proxy or Amazon failed.
+func isProxyFailureStatus(statusCode int) bool {
+	return statusCode == http.StatusProxyAuthRequired ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
+func (p *ProxyPool) recordError(proxy *pooledProxy, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	proxy.stats.Errors++
+	p.recordFailure(proxy, err.Error())
+}
+
+// recordRobotCheck counts a robot check as a failure rather than a success,
+// since the proxy got no wishlist from Amazon.
+func (p *ProxyPool) recordRobotCheck(proxy *pooledProxy, latency time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.recordLatency(proxy, latency)
+	proxy.stats.RobotChecks++
+	p.recordFailure(proxy, ErrRobot.Error())
+}
+
+// recordLatency must be called with the mutex held.
+func (p *ProxyPool) recordLatency(proxy *pooledProxy, latency time.Duration) {
+	proxy.responses++
+	proxy.totalLatency += latency
+	proxy.stats.AverageLatency = proxy.totalLatency / time.Duration(proxy.responses)
+}
+
+// recordFailure must be called with the mutex held.
+func (p *ProxyPool) recordFailure(proxy *pooledProxy, message string) {
+	proxy.stats.LastError = message
+	proxy.stats.ConsecutiveFailures++
+
+	maxFailures := p.MaxFailures
+	if maxFailures < 1 {
+		maxFailures = DefaultProxyMaxFailures
+	}
+	if proxy.stats.ConsecutiveFailures < maxFailures {
+		return
+	}
+
+	benchDuration := p.BenchDuration
+	if benchDuration <= 0 {
+		benchDuration = DefaultProxyBenchDuration
+	}
+	proxy.stats.BenchedUntil = p.now().Add(benchDuration)
+	proxy.stats.ConsecutiveFailures = 0
+}
+
+// transport returns an HTTP transport that sends requests through the pool
+// and records how each proxy does. The transport is built once per pool, so
+// connections to the proxies are reused across collectors and wishlists.
+func (p *ProxyPool) transport() http.RoundTripper {
+	p.transportOnce.Do(func() {
+		// Set up like http.DefaultTransport by hand, since Transport.Clone
+		// needs Go 1.13.
+		transport := &http.Transport{
+			Proxy: func(r *http.Request) (*url.URL, error) {
+				proxy, ok := r.Context().Value(pooledProxyKey{}).(*pooledProxy)
+				if !ok {
+					return nil, errors.New("No proxy was picked for the request")
+				}
+				return transportProxyURL(proxy.uri), nil
+			},
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		}
+		p.roundTripper = &proxyPoolTransport{pool: p, transport: transport}
+	})
+	return p.roundTripper
+}
+
+// RoundTrip sends the request through the healthiest proxy in the pool. The
+// response body is read here, rather than by colly, so that robot checks can
+// be blamed on the proxy that got them.
+func (t *proxyPoolTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	proxy := t.pool.pick()
+	r = r.Clone(context.WithValue(r.Context(), pooledProxyKey{}, proxy))
+
+	start := t.pool.now()
+	response, err := t.transport.RoundTrip(r)
+	if err != nil {
+		t.pool.recordError(proxy, err)
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		t.pool.recordError(proxy, err)
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	latency := t.pool.now().Sub(start)
+	if isRobotPage(body, marketplaceForHost(r.URL.Hostname())) {
+		t.pool.recordRobotCheck(proxy, latency)
+	} else {
+		t.pool.recordResponse(proxy, latency, response.StatusCode)
+	}
+
+	return response, nil
+}
+
+func (proxy *pooledProxy) isBenched(now time.Time) bool {
+	return now.Before(proxy.stats.BenchedUntil)
+}
+
+func (proxy *pooledProxy) failureRate() float64 {
+	if proxy.stats.Requests < 1 {
+		return 0
+	}
+	failures := proxy.stats.Errors + proxy.stats.RobotChecks
+	return float64(failures) / float64(proxy.stats.Requests)
+}
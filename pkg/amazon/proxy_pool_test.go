@@ -0,0 +1,152 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProxyPool(t *testing.T) {
+	pool, err := NewProxyPool("127.0.0.1:9050", "socks5://127.0.0.1:9050", "http://10.0.0.1:3128")
+	require.NoError(t, err)
+	require.Len(t, pool.Stats(), 2, "should skip duplicate proxies")
+
+	_, err = NewProxyPool()
+	require.Error(t, err)
+
+	_, err = NewProxyPool("127.0.0.1:9050", "ftp://10.0.0.1:21")
+	require.Error(t, err)
+}
+
+func TestProxyPoolRotatesAmongHealthyProxies(t *testing.T) {
+	pool, err := NewProxyPool("http://10.0.0.1:3128", "http://10.0.0.2:3128")
+	require.NoError(t, err)
+
+	require.Equal(t, "10.0.0.1:3128", pickProxyHost(t, pool))
+	require.Equal(t, "10.0.0.2:3128", pickProxyHost(t, pool))
+	require.Equal(t, "10.0.0.1:3128", pickProxyHost(t, pool))
+}
+
+func TestProxyPoolPrefersHealthyProxies(t *testing.T) {
+	pool, err := NewProxyPool("http://10.0.0.1:3128", "http://10.0.0.2:3128")
+	require.NoError(t, err)
+
+	pickProxyHost(t, pool)
+	pool.recordError(pool.proxies[0], errors.New("connection refused"))
+	pickProxyHost(t, pool)
+	pool.recordResponse(pool.proxies[1], time.Second, http.StatusOK)
+
+	require.Equal(t, "10.0.0.2:3128", pickProxyHost(t, pool))
+	require.Equal(t, "10.0.0.2:3128", pickProxyHost(t, pool))
+
+	stats := pool.Stats()
+	require.Equal(t, 1, stats[0].Errors)
+	require.Equal(t, "connection refused", stats[0].LastError)
+	require.False(t, stats[0].Benched)
+	require.Equal(t, 1, stats[1].Successes)
+	require.Equal(t, time.Second, stats[1].AverageLatency)
+}
+
+func TestProxyPoolCountsFailedStatuses(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		failed     bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusProxyAuthRequired, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+	}
+
+	for _, test := range tests {
+		pool, err := NewProxyPool("http://10.0.0.1:3128")
+		require.NoError(t, err)
+
+		pool.recordResponse(pool.proxies[0], time.Second, test.statusCode)
+
+		stats := pool.Stats()
+		if test.failed {
+			require.Equal(t, 1, stats[0].Errors, "status %d", test.statusCode)
+			require.Equal(t, fmt.Sprintf("Status %d", test.statusCode), stats[0].LastError)
+		} else {
+			require.Equal(t, 1, stats[0].Successes, "status %d", test.statusCode)
+		}
+	}
+}
+
+func TestProxyPoolBenchesFailingProxies(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	pool, err := NewProxyPool("http://10.0.0.1:3128", "http://10.0.0.2:3128")
+	require.NoError(t, err)
+	pool.MaxFailures = 2
+	pool.BenchDuration = time.Minute
+	pool.now = func() time.Time { return now }
+
+	pool.recordRobotCheck(pool.proxies[1], time.Second)
+	pool.recordResponse(pool.proxies[1], time.Second, http.StatusServiceUnavailable)
+
+	stats := pool.Stats()
+	require.True(t, stats[1].Benched)
+	require.Equal(t, now.Add(time.Minute), stats[1].BenchedUntil)
+	require.Equal(t, 1, stats[1].RobotChecks)
+	require.Equal(t, 1, stats[1].Errors)
+	require.Equal(t, "Status 503", stats[1].LastError)
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "10.0.0.1:3128", pickProxyHost(t, pool))
+	}
+
+	// With every proxy benched, the one back soonest is used.
+	now = now.Add(30 * time.Second)
+	pool.recordError(pool.proxies[0], errors.New("timeout"))
+	pool.recordError(pool.proxies[0], errors.New("timeout"))
+	require.Equal(t, "10.0.0.2:3128", pickProxyHost(t, pool))
+
+	now = now.Add(time.Minute)
+	require.False(t, pool.Stats()[1].Benched)
+	require.Contains(t, pool.String(), "http://10.0.0.1:3128: 3 request(s), 0 success(es), 2 error(s)")
+}
+
+func TestItemsRecordsRobotChecksPerProxy(t *testing.T) {
+	id := "123abc"
+	robotProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<form method="get" action="/errors/validateCaptcha"></form>`))
+	}))
+	defer robotProxy.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, "http://wishlist.example.com")
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.Layouts = []Layout{LayoutStandard}
+	require.NoError(t, wishlist.SetProxyURLs(robotProxy.URL))
+
+	_, err = wishlist.Items()
+	require.Equal(t, ErrRobot, err)
+
+	stats := wishlist.ProxyPool().Stats()
+	require.Equal(t, 1, stats[0].Requests)
+	require.Equal(t, 0, stats[0].Successes, "a robot check is not a success")
+	require.Equal(t, 1, stats[0].RobotChecks)
+	require.Equal(t, 1, stats[0].ConsecutiveFailures)
+}
+
+func TestProxyPoolReusesTransport(t *testing.T) {
+	pool, err := NewProxyPool("http://10.0.0.1:3128")
+	require.NoError(t, err)
+	require.True(t, pool.transport() == pool.transport(),
+		"should build the transport once per pool")
+}
+
+func pickProxyHost(t *testing.T, pool *ProxyPool) string {
+	return pool.pick().uri.Host
+}
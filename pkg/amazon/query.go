@@ -0,0 +1,188 @@
+package amazon
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Reveal determines which items on a wishlist are shown, based on whether
+// they have been purchased.
+type Reveal string
+
+// Sort determines the order of the items on a wishlist.
+type Sort string
+
+// Filter narrows down the items shown on a wishlist.
+type Filter string
+
+const (
+	// RevealUnpurchased shows only items that have not been bought yet. This
+	// is the default.
+	RevealUnpurchased Reveal = "unpurchased"
+
+	// RevealPurchased shows only items that have already been bought.
+	RevealPurchased Reveal = "purchased"
+
+	// RevealAll shows items whether or not they have been bought.
+	RevealAll Reveal = "all"
+
+	// SortDateAdded sorts items by when they were added, newest first. This
+	// is the default.
+	SortDateAdded Sort = "date"
+
+	// SortPriority sorts items by the priority the wishlist owner gave them.
+	SortPriority Sort = "priority"
+
+	// SortPriceLowToHigh sorts items by price, cheapest first.
+	SortPriceLowToHigh Sort = "universal-price"
+
+	// SortPriceHighToLow sorts items by price, most expensive first.
+	SortPriceHighToLow Sort = "universal-price-desc"
+
+	// SortLastUpdated sorts items by when they were last changed.
+	SortLastUpdated Sort = "last-updated"
+
+	// SortTitle sorts items alphabetically by name.
+	SortTitle Sort = "universal-title"
+
+	// FilterDefault applies no filter beyond Reveal. This is the default.
+	FilterDefault Filter = "DEFAULT"
+
+	// FilterPriceDrop shows only items whose price has dropped since they
+	// were added to the wishlist.
+	FilterPriceDrop Filter = "price-drop"
+
+	// FilterPrime shows only items eligible for Prime shipping.
+	FilterPrime Filter = "prime"
+)
+
+var (
+	// listKindQueries lists the query options each kind of list supports. The
+	// first of each is the default. Kinds with no options for a field don't
+	// send that parameter to Amazon at all.
+	listKindQueries = map[ListKind]struct {
+		reveals []string
+		sorts   []string
+		filters []string
+	}{
+		ListKindWishlist: {
+			reveals: []string{string(RevealUnpurchased), string(RevealPurchased),
+				string(RevealAll)},
+			sorts: []string{string(SortDateAdded), string(SortPriority),
+				string(SortPriceLowToHigh), string(SortPriceHighToLow),
+				string(SortLastUpdated), string(SortTitle)},
+			filters: []string{string(FilterDefault), string(FilterPriceDrop),
+				string(FilterPrime)},
+		},
+		// Idea Lists don't track purchases, so there's nothing to reveal.
+		ListKindIdeaList: {
+			sorts: []string{string(SortDateAdded), string(SortPriceLowToHigh),
+				string(SortPriceHighToLow)},
+		},
+		ListKindBabyRegistry: {
+			reveals: []string{string(RevealUnpurchased), string(RevealPurchased),
+				string(RevealAll)},
+			sorts: []string{string(SortDateAdded), string(SortPriceLowToHigh),
+				string(SortPriceHighToLow)},
+		},
+		ListKindWeddingRegistry: {
+			reveals: []string{string(RevealUnpurchased), string(RevealPurchased),
+				string(RevealAll)},
+			sorts: []string{string(SortDateAdded), string(SortPriceLowToHigh),
+				string(SortPriceHighToLow)},
+		},
+	}
+)
+
+// Query holds the options that control which items on a wishlist are loaded
+// and in what order. Empty fields use the default for that option.
+type Query struct {
+	Reveal Reveal
+	Sort   Sort
+	Filter Filter
+}
+
+// DefaultQuery returns the options used to load a wishlist unless others are
+// given: unpurchased items, newest first.
+func DefaultQuery() Query {
+	return defaultQueryFor(ListKindWishlist)
+}
+
+// Validate returns an error if any of the options are not supported by the
+// given kind of list.
+func (q Query) Validate(kind ListKind) error {
+	supported, ok := listKindQueries[kind]
+	if !ok {
+		return fmt.Errorf("No query options are supported for %s lists", kind)
+	}
+
+	q = q.withDefaults(kind)
+
+	if q.Reveal != "" && !containsString(supported.reveals, string(q.Reveal)) {
+		return fmt.Errorf("Reveal '%s' is not supported for %s lists", q.Reveal, kind)
+	}
+	if q.Sort != "" && !containsString(supported.sorts, string(q.Sort)) {
+		return fmt.Errorf("Sort '%s' is not supported for %s lists", q.Sort, kind)
+	}
+	if q.Filter != "" && !containsString(supported.filters, string(q.Filter)) {
+		return fmt.Errorf("Filter '%s' is not supported for %s lists", q.Filter, kind)
+	}
+
+	return nil
+}
+
+func defaultQueryFor(kind ListKind) Query {
+	supported := listKindQueries[kind]
+	query := Query{}
+	if len(supported.reveals) > 0 {
+		query.Reveal = Reveal(supported.reveals[0])
+	}
+	if len(supported.sorts) > 0 {
+		query.Sort = Sort(supported.sorts[0])
+	}
+	if len(supported.filters) > 0 {
+		query.Filter = Filter(supported.filters[0])
+	}
+	return query
+}
+
+func (q Query) withDefaults(kind ListKind) Query {
+	defaults := defaultQueryFor(kind)
+	if q.Reveal == "" {
+		q.Reveal = defaults.Reveal
+	}
+	if q.Sort == "" {
+		q.Sort = defaults.Sort
+	}
+	if q.Filter == "" {
+		q.Filter = defaults.Filter
+	}
+	return q
+}
+
+// values returns the query string parameters Amazon expects for these
+// options on the given kind of list.
+func (q Query) values(kind ListKind) url.Values {
+	q = q.withDefaults(kind)
+
+	values := url.Values{}
+	if q.Reveal != "" {
+		values.Set("reveal", string(q.Reveal))
+	}
+	if q.Sort != "" {
+		values.Set("sort", string(q.Sort))
+	}
+	if q.Filter != "" {
+		values.Set("filter", string(q.Filter))
+	}
+	return values
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package amazon
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryValidate(t *testing.T) {
+	require.NoError(t, DefaultQuery().Validate(ListKindWishlist))
+	require.NoError(t, Query{}.Validate(ListKindWishlist))
+	require.NoError(t, Query{Reveal: RevealAll, Sort: SortPriceHighToLow}.Validate(ListKindWishlist))
+
+	require.Error(t, Query{Reveal: Reveal("some")}.Validate(ListKindWishlist))
+	require.Error(t, Query{Sort: Sort("random")}.Validate(ListKindWishlist))
+	require.NoError(t, Query{Filter: FilterPriceDrop}.Validate(ListKindWishlist))
+	require.NoError(t, Query{Filter: FilterPrime}.Validate(ListKindWishlist))
+	require.Error(t, Query{Filter: Filter("BOOKS")}.Validate(ListKindWishlist))
+	require.Error(t, DefaultQuery().Validate(ListKind(-1)))
+
+	require.NoError(t, Query{Sort: SortPriceLowToHigh}.Validate(ListKindIdeaList))
+	require.Error(t, Query{Reveal: RevealPurchased}.Validate(ListKindIdeaList))
+	require.Error(t, Query{Sort: SortPriority}.Validate(ListKindBabyRegistry))
+	require.Error(t, Query{Filter: FilterPrime}.Validate(ListKindBabyRegistry))
+	require.NoError(t, Query{Reveal: RevealAll}.Validate(ListKindWeddingRegistry))
+}
+
+func TestSetQuery(t *testing.T) {
+	wishlist, err := NewWishlistFromID("3I6EQPZ8OB1DT")
+	require.NoError(t, err)
+	require.Equal(t, DefaultQuery(), wishlist.Query())
+
+	err = wishlist.SetQuery(Query{Reveal: RevealPurchased, Sort: SortPriority})
+	require.NoError(t, err)
+	require.Equal(t, Query{Reveal: RevealPurchased, Sort: SortPriority, Filter: FilterDefault},
+		wishlist.Query())
+
+	urls := wishlist.URLs()
+	require.Len(t, urls, 1)
+	uri, err := url.Parse(urls[0])
+	require.NoError(t, err)
+	require.Equal(t, "/hz/wishlist/ls/3I6EQPZ8OB1DT", uri.Path)
+	require.Equal(t, "purchased", uri.Query().Get("reveal"))
+	require.Equal(t, "priority", uri.Query().Get("sort"))
+	require.Equal(t, "DEFAULT", uri.Query().Get("filter"))
+
+	err = wishlist.SetQuery(Query{Reveal: RevealPurchased, Filter: FilterPriceDrop})
+	require.NoError(t, err)
+	uri, err = url.Parse(wishlist.URLs()[0])
+	require.NoError(t, err)
+	require.Equal(t, "price-drop", uri.Query().Get("filter"))
+
+	err = wishlist.SetQuery(Query{Sort: Sort("bogus")})
+	require.Error(t, err)
+	require.Equal(t, RevealPurchased, wishlist.Query().Reveal, "should keep the previous query")
+}
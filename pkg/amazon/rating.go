@@ -0,0 +1,62 @@
+package amazon
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxStars is the top of the scale Amazon rates products on.
+	maxStars = 5.0
+)
+
+var (
+	decimalRegexp  = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+	nonDigitRegexp = regexp.MustCompile(`\D`)
+)
+
+// parseStars extracts the numeric rating from a localized description such as
+// "4.0 out of 5 stars", "4,0 von 5 Sternen", or "5つ星のうち4.0". Marketplaces
+// disagree on word order, so rather than matching phrases we take the numbers
+// in the description: one of them is the scale (5) and the other the rating.
+func parseStars(text string) (float64, bool) {
+	matches := decimalRegexp.FindAllString(text, 2)
+	if len(matches) < 1 {
+		return 0, false
+	}
+
+	stars := -1.0
+	for _, match := range matches {
+		value, err := strconv.ParseFloat(strings.Replace(match, ",", ".", 1), 64)
+		if err != nil {
+			return 0, false
+		}
+		if stars < 0 || value < stars {
+			stars = value
+		}
+	}
+
+	if stars > maxStars {
+		return 0, false
+	}
+
+	return stars, true
+}
+
+// parseCount extracts a whole number from localized text such as "1,234",
+// "1.234", or "1 234", where the thousands separator varies by marketplace.
+func parseCount(text string) (int, error) {
+	digits := nonDigitRegexp.ReplaceAllString(text, "")
+	if len(digits) < 1 {
+		return 0, fmt.Errorf("No number found in '%s'", strings.TrimSpace(text))
+	}
+
+	count, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
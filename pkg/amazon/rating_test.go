@@ -0,0 +1,71 @@
+package amazon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStars(t *testing.T) {
+	tests := []struct {
+		tld      string
+		text     string
+		expected float64
+	}{
+		{"ca", "4.5 out of 5 stars", 4.5},
+		{"co.jp", "5つ星のうち4.0", 4.0},
+		{"co.uk", "3.9 out of 5 stars", 3.9},
+		{"com", "4.0 out of 5 stars", 4.0},
+		{"com", "5.0 out of 5 stars", 5.0},
+		{"com.br", "4,6 de 5 estrelas", 4.6},
+		{"de", "4,0 von 5 Sternen", 4.0},
+		{"es", "4,2 de 5 estrellas", 4.2},
+		{"fr", "4,3 sur 5 étoiles", 4.3},
+		{"in", "1.0 out of 5 stars", 1.0},
+		{"it", "4,4 su 5 stelle", 4.4},
+	}
+
+	for _, test := range tests {
+		stars, ok := parseStars(test.text)
+		require.True(t, ok, test.tld)
+		require.Equal(t, test.expected, stars, test.tld)
+	}
+}
+
+func TestParseStarsInvalid(t *testing.T) {
+	for _, text := range []string{"", "No customer reviews", "930"} {
+		_, ok := parseStars(text)
+		require.False(t, ok, text)
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		tld      string
+		text     string
+		expected int
+	}{
+		{"ca", "1,234", 1234},
+		{"co.jp", "1,234", 1234},
+		{"co.uk", "12,345 ratings", 12345},
+		{"com", "\n930\n", 930},
+		{"com.br", "1.234", 1234},
+		{"de", "1.234 Sternebewertungen", 1234},
+		{"es", "1.234", 1234},
+		{"fr", "1 234", 1234},
+		{"fr", "1 234 évaluations", 1234},
+		{"in", "1,23,456", 123456},
+		{"it", "1.234", 1234},
+	}
+
+	for _, test := range tests {
+		count, err := parseCount(test.text)
+		require.NoError(t, err, test.tld)
+		require.Equal(t, test.expected, count, test.tld)
+	}
+}
+
+func TestParseCountInvalid(t *testing.T) {
+	_, err := parseCount("  ")
+	require.Error(t, err)
+}
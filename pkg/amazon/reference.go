@@ -0,0 +1,168 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ListKind identifies the type of Amazon list a URL points to.
+type ListKind int
+
+const (
+	// ListKindWishlist is a regular Amazon wishlist or shopping list.
+	ListKindWishlist ListKind = iota
+
+	// ListKindIdeaList is an Idea List, a curated list of products with
+	// notes, which has no quantities or purchase tracking.
+	ListKindIdeaList
+
+	// ListKindBabyRegistry is a Baby Registry. How many of an item have been
+	// purchased from the registry is its PurchasedCount.
+	ListKindBabyRegistry
+
+	// ListKindWeddingRegistry is a Wedding Registry. How many of an item have
+	// been purchased from the registry is its PurchasedCount.
+	ListKindWeddingRegistry
+)
+
+var (
+	// listPathRegexps match the paths Amazon has used for wishlists over the
+	// years, capturing the list ID. Anything after the ID, such as
+	// "/ref=cm_sw_r_cp_api", is ignored.
+	listPathRegexps = []struct {
+		re   *regexp.Regexp
+		kind ListKind
+	}{
+		{regexp.MustCompile(`^/hz/wishlist/(?:ls|genericItemsPage|printview)/([A-Za-z0-9]+)(?:/.*)?$`), ListKindWishlist},
+		{regexp.MustCompile(`^/(?:gp/)?registry/wishlist/([A-Za-z0-9]+)(?:/.*)?$`), ListKindWishlist},
+		{regexp.MustCompile(`^/gp/aw/ls/([A-Za-z0-9]+)(?:/.*)?$`), ListKindWishlist},
+		{regexp.MustCompile(`^/wishlist/([A-Za-z0-9]+)(?:/.*)?$`), ListKindWishlist},
+		{regexp.MustCompile(`^/ideas/(?:amzn1\.account\.[A-Za-z0-9]+/)?([A-Za-z0-9]+)(?:/.*)?$`), ListKindIdeaList},
+		// Registry links usually include a slug with the registrants' names
+		// before the ID, e.g., "/baby-reg/jane-doe-july-2020/2X3Y4Z5ABCDEF".
+		{regexp.MustCompile(`^/baby-reg/(?:[^/]+/)?([A-Za-z0-9]+)(?:/ref=[^/]*)?/?$`), ListKindBabyRegistry},
+		{regexp.MustCompile(`^/wedding/(?:[^/]+/)?(?:registry|guest-view)/([A-Za-z0-9]+)(?:/.*)?$`), ListKindWeddingRegistry},
+	}
+
+	// listQueryPaths are paths where the list ID is given in the query string
+	// rather than the path, mapped to the name of the query parameter.
+	listQueryPaths = []struct {
+		re    *regexp.Regexp
+		param string
+		kind  ListKind
+	}{
+		{regexp.MustCompile(`^/hz/wishlist/ls/?$`), "lid", ListKindWishlist},
+		{regexp.MustCompile(`^/gp/aw/ls/?$`), "lid", ListKindWishlist},
+		{regexp.MustCompile(`^/gp/registry/(?:wishlist/?|registry\.html)$`), "id", ListKindWishlist},
+	}
+
+	listIDRegexp = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+)
+
+// WishlistRef identifies a list on Amazon, as parsed from a URL to it.
+type WishlistRef struct {
+	// Marketplace is the Amazon marketplace the list is on.
+	Marketplace *Marketplace
+
+	// Kind is the type of list.
+	Kind ListKind
+
+	// ID is the unique identifier of the list on Amazon.
+	ID string
+
+	// BaseURL is the scheme and host to request the list from, e.g.,
+	// "https://www.amazon.de".
+	BaseURL string
+
+	// URL is the URL the reference was parsed from.
+	URL string
+}
+
+// String returns a short description of the list kind.
+func (k ListKind) String() string {
+	switch k {
+	case ListKindWishlist:
+		return "wishlist"
+	case ListKindIdeaList:
+		return "idea list"
+	case ListKindBabyRegistry:
+		return "baby registry"
+	case ListKindWeddingRegistry:
+		return "wedding registry"
+	}
+	return "unknown"
+}
+
+// ParseWishlistURL parses any of the URL shapes Amazon uses for lists, e.g.,
+// "https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT/ref=nav_wishlist_lists_1"
+// or "https://smile.amazon.com/gp/registry/wishlist/3I6EQPZ8OB1DT", as well as
+// links to Idea Lists, Baby Registries, and Wedding Registries. Returns an
+// error if the URL is not to an Amazon list.
+func ParseWishlistURL(urlStr string) (*WishlistRef, error) {
+	urlStr = strings.TrimSpace(urlStr)
+	if len(urlStr) < 1 {
+		return nil, errors.New("No Amazon wishlist URL provided")
+	}
+
+	uri, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !uri.IsAbs() || uri.Host == "" {
+		return nil, fmt.Errorf("URL '%s' is not an absolute URL to an Amazon wishlist",
+			urlStr)
+	}
+
+	kind, id, ok := parseListPath(uri)
+	if !ok && IsShortLink(urlStr) {
+		return nil, fmt.Errorf("URL '%s' is a short link, see NewWishlistFromShortLink", urlStr)
+	}
+	if !ok {
+		return nil, fmt.Errorf("URL '%s' is not a link to an Amazon wishlist", urlStr)
+	}
+
+	ref := &WishlistRef{Kind: kind, ID: id, URL: urlStr}
+	if m, ok := MarketplaceForHost(uri.Hostname()); ok {
+		// Use the main site even for links to smile.amazon.com or the mobile
+		// site, since that's the markup we know how to parse.
+		ref.Marketplace = m
+		ref.BaseURL = m.URL()
+	} else {
+		ref.Marketplace = defaultMarketplace()
+		ref.BaseURL = fmt.Sprintf("%s://%s", uri.Scheme, uri.Host)
+	}
+
+	return ref, nil
+}
+
+// String returns the URL the reference was parsed from.
+func (r *WishlistRef) String() string {
+	return r.URL
+}
+
+func parseListPath(uri *url.URL) (ListKind, string, bool) {
+	path := uri.EscapedPath()
+
+	for _, pattern := range listPathRegexps {
+		if matches := pattern.re.FindStringSubmatch(path); matches != nil {
+			return pattern.kind, matches[1], true
+		}
+	}
+
+	query := uri.Query()
+	for _, pattern := range listQueryPaths {
+		if !pattern.re.MatchString(path) {
+			continue
+		}
+		id := query.Get(pattern.param)
+		if listIDRegexp.MatchString(id) {
+			return pattern.kind, id, true
+		}
+	}
+
+	return ListKindWishlist, "", false
+}
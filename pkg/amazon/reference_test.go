@@ -0,0 +1,132 @@
+package amazon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWishlistURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		id      string
+		baseURL string
+	}{
+		{"https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT/", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT?ref_=wl_share", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT/ref=nav_wishlist_lists_1", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT/ref=cm_sw_r_cp_ep_ws_abc?_encoding=UTF8&type=wishlist", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/ls?lid=3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/ls/?lid=3I6EQPZ8OB1DT&ty=wishlist", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/genericItemsPage/3I6EQPZ8OB1DT?type=wishlist", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/hz/wishlist/printview/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/gp/registry/wishlist/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/gp/registry/wishlist/3I6EQPZ8OB1DT/ref=cm_wl_huc_view", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/gp/registry/wishlist/?id=3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/gp/registry/registry.html?ie=UTF8&id=3I6EQPZ8OB1DT&type=wishlist", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/registry/wishlist/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/registry/wishlist/3I6EQPZ8OB1DT/ref=cm_sw_em_r_wl_ip", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/wishlist/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/gp/aw/ls/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.com/gp/aw/ls?lid=3I6EQPZ8OB1DT&ty=wishlist", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"http://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://smile.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://smile.amazon.com/gp/registry/wishlist/3I6EQPZ8OB1DT/ref=smi_www_rco2_go_smi", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://m.amazon.com/gp/aw/ls/3I6EQPZ8OB1DT", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"  https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT  ", "3I6EQPZ8OB1DT", "https://www.amazon.com"},
+		{"https://www.amazon.ca/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.ca"},
+		{"https://www.amazon.co.uk/hz/wishlist/ls/2X3Y4Z5ABCDEF?ref_=wl_share", "2X3Y4Z5ABCDEF", "https://www.amazon.co.uk"},
+		{"https://www.amazon.co.jp/hz/wishlist/ls/2X3Y4Z5ABCDEF/ref=nav_wishlist_lists_1", "2X3Y4Z5ABCDEF", "https://www.amazon.co.jp"},
+		{"https://www.amazon.com.br/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.com.br"},
+		{"https://www.amazon.com.au/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.com.au"},
+		{"https://www.amazon.com.mx/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.com.mx"},
+		{"https://www.amazon.de/hz/wishlist/ls/2X3Y4Z5ABCDEF/", "2X3Y4Z5ABCDEF", "https://www.amazon.de"},
+		{"https://www.amazon.de/registry/wishlist/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.de"},
+		{"https://www.amazon.es/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.es"},
+		{"https://www.amazon.fr/gp/registry/wishlist/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.fr"},
+		{"https://www.amazon.in/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.in"},
+		{"https://www.amazon.it/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.it"},
+		{"https://www.amazon.nl/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.nl"},
+		{"https://www.amazon.se/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.se"},
+		{"https://www.amazon.pl/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.pl"},
+		{"https://www.amazon.sg/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.sg"},
+		{"https://www.amazon.ae/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.ae"},
+		{"https://www.amazon.sa/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.sa"},
+		{"https://www.amazon.com.tr/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.com.tr"},
+		{"https://www.amazon.eg/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.eg"},
+		{"https://www.amazon.com.be/hz/wishlist/ls/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", "https://www.amazon.com.be"},
+		{"http://127.0.0.1:8080/hz/wishlist/ls/123abc", "123abc", "http://127.0.0.1:8080"},
+	}
+
+	for _, test := range tests {
+		ref, err := ParseWishlistURL(test.url)
+		require.NoError(t, err, test.url)
+		require.Equal(t, test.id, ref.ID, test.url)
+		require.Equal(t, test.baseURL, ref.BaseURL, test.url)
+		require.Equal(t, ListKindWishlist, ref.Kind, test.url)
+		require.NotNil(t, ref.Marketplace, test.url)
+		require.Equal(t, strings.TrimSpace(test.url), ref.URL)
+	}
+}
+
+func TestParseWishlistURLMarketplace(t *testing.T) {
+	ref, err := ParseWishlistURL("https://www.amazon.com.br/hz/wishlist/ls/2X3Y4Z5ABCDEF")
+	require.NoError(t, err)
+	require.Equal(t, "amazon.com.br", ref.Marketplace.Domain)
+}
+
+func TestParseWishlistURLKinds(t *testing.T) {
+	tests := []struct {
+		url  string
+		id   string
+		kind ListKind
+	}{
+		{"https://www.amazon.com/ideas/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", ListKindIdeaList},
+		{"https://www.amazon.com/ideas/amzn1.account.ABC123XYZ/2X3Y4Z5ABCDEF?ref_=idea_share", "2X3Y4Z5ABCDEF", ListKindIdeaList},
+		{"https://www.amazon.com/baby-reg/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", ListKindBabyRegistry},
+		{"https://www.amazon.com/baby-reg/jane-doe-july-2020/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", ListKindBabyRegistry},
+		{"https://www.amazon.co.uk/baby-reg/jane-doe-july-2020/2X3Y4Z5ABCDEF/ref=br_share", "2X3Y4Z5ABCDEF", ListKindBabyRegistry},
+		{"https://www.amazon.com/wedding/registry/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", ListKindWeddingRegistry},
+		{"https://www.amazon.com/wedding/jane-doe-john-smith-june-2021/registry/2X3Y4Z5ABCDEF", "2X3Y4Z5ABCDEF", ListKindWeddingRegistry},
+		{"https://www.amazon.com/wedding/guest-view/2X3Y4Z5ABCDEF/ref=wr_share", "2X3Y4Z5ABCDEF", ListKindWeddingRegistry},
+	}
+
+	for _, test := range tests {
+		ref, err := ParseWishlistURL(test.url)
+		require.NoError(t, err, test.url)
+		require.Equal(t, test.id, ref.ID, test.url)
+		require.Equal(t, test.kind, ref.Kind, test.url)
+	}
+}
+
+func TestParseWishlistURLErrors(t *testing.T) {
+	urls := []string{
+		"",
+		"   ",
+		"/hz/wishlist/ls/3I6EQPZ8OB1DT",
+		"www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT",
+		"https://www.amazon.com/",
+		"https://www.amazon.com/hz/wishlist/ls",
+		"https://www.amazon.com/hz/wishlist/ls/",
+		"https://www.amazon.com/hz/wishlist/ls?lid=",
+		"https://www.amazon.com/hz/wishlist/intro",
+		"https://www.amazon.com/dp/B0018CLTKE",
+		"https://www.amazon.com/gp/product/B0018CLTKE",
+		"https://www.amazon.com/s?k=cat+litter",
+		"https://www.amazon.com/gp/registry/wishlist/",
+		"https://www.amazon.com/ap/signin?openid.return_to=https%3A%2F%2Fwww.amazon.com%2Fhz%2Fwishlist%2Fls%2F3I6EQPZ8OB1DT",
+		"https://www.amazon.com/hz/wishlist/ls/3I6EQ%2FPZ8OB1DT",
+		"https://www.amazon.com/ideas",
+		"https://www.amazon.com/baby-reg/",
+		"https://www.amazon.com/baby-reg/jane-doe/2X3Y4Z5ABCDEF/extra/path",
+		"https://www.amazon.com/wedding/home",
+	}
+
+	for _, url := range urls {
+		_, err := ParseWishlistURL(url)
+		require.Error(t, err, url)
+	}
+}
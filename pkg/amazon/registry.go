@@ -0,0 +1,144 @@
+package amazon
+
+import (
+	"strings"
+
+	"github.com/gocolly/colly"
+)
+
+const (
+	// registryNextPageSelector matches the "Next" link of Amazon's standard
+	// pagination control, used by Idea Lists and registries.
+	registryNextPageSelector = "ul.a-pagination li.a-last a"
+)
+
+var (
+	// registryParsers describe the markup of each kind of list other than a
+	// wishlist.
+	registryParsers = map[ListKind]registryParser{
+		ListKindIdeaList: {
+			nameSelector:   "#idea-list-title",
+			itemSelector:   ".idea-list-item",
+			titleSelector:  ".idea-list-item-link",
+			imageSelector:  ".idea-list-item-link img",
+			ratingSelector: ".a-icon-star-small .a-icon-alt",
+			noteSelector:   ".idea-list-item-note",
+		},
+		ListKindBabyRegistry: {
+			nameSelector:      "#br-registry-name",
+			itemSelector:      ".br-item",
+			titleSelector:     ".br-item-title",
+			imageSelector:     "img.br-item-image",
+			ratingSelector:    ".a-icon-star-small .a-icon-alt",
+			requestedSelector: ".br-item-requested",
+			purchasedSelector: ".br-item-purchased",
+			noteSelector:      ".br-item-note",
+		},
+		ListKindWeddingRegistry: {
+			nameSelector:      "#wr-registry-name",
+			itemSelector:      ".wr-item",
+			titleSelector:     ".wr-item-title",
+			imageSelector:     "img.wr-item-image",
+			ratingSelector:    ".a-icon-star-small .a-icon-alt",
+			requestedSelector: ".wr-item-requested",
+			purchasedSelector: ".wr-item-received",
+			noteSelector:      ".wr-item-note",
+		},
+	}
+)
+
+// registryParser holds the selectors for reading items from an Idea List or
+// registry. Registries count what gift-givers have bought for the registrant
+// rather than what the owner has; that count is read into Item.PurchasedCount.
+type registryParser struct {
+	nameSelector      string
+	itemSelector      string
+	titleSelector     string
+	imageSelector     string
+	ratingSelector    string
+	requestedSelector string
+	purchasedSelector string
+	noteSelector      string
+}
+
+// nameSelector returns the selector for the element holding the name of this
+// kind of list.
+func (w *Wishlist) nameSelector() string {
+	if parser, ok := registryParsers[w.kind]; ok {
+		return parser.nameSelector
+	}
+	return listNameSelector
+}
+
+func (w *Wishlist) onRegistryItems(c *colly.Collector, parser registryParser) {
+	c.OnHTML(parser.itemSelector, func(el *colly.HTMLElement) {
+		w.onRegistryItem(parser, el)
+	})
+	c.OnHTML(registryNextPageSelector, func(link *colly.HTMLElement) {
+		w.onLoadMoreLink(c, link)
+	})
+}
+
+func (w *Wishlist) onRegistryItem(parser registryParser, el *colly.HTMLElement) {
+	asin := el.Attr("data-asin")
+	id := el.Attr("data-itemid")
+	if len(id) < 1 {
+		// Idea List items are identified by their product alone.
+		id = asin
+	}
+	if len(id) < 1 {
+		return
+	}
+
+	el.ForEachWithBreak(parser.titleSelector, func(index int, title *colly.HTMLElement) bool {
+		name := title.Attr("title")
+		if len(name) < 1 {
+			name = collapseSpace(title.Text)
+		}
+		if len(name) < 1 {
+			return true
+		}
+
+		directURL := ""
+		if relativeURL := title.Attr("href"); len(relativeURL) > 0 {
+			directURL = title.Request.AbsoluteURL(relativeURL)
+		} else if len(asin) > 0 {
+			directURL = ProductURL(w.baseURL, asin, "")
+		}
+
+		w.addItem(id, name, directURL)
+		return false
+	})
+
+	item := w.items[id]
+	if item == nil {
+		return
+	}
+	if item.ASIN == "" {
+		item.ASIN = asin
+	}
+
+	w.onItemDetails(id, el)
+
+	el.ForEachWithBreak(parser.imageSelector, func(index int, image *colly.HTMLElement) bool {
+		w.onImage(id, image)
+		return false
+	})
+	el.ForEachWithBreak(parser.ratingSelector, func(index int, ratingEl *colly.HTMLElement) bool {
+		w.onRating(id, ratingEl)
+		return false
+	})
+	if parser.requestedSelector != "" {
+		if count, err := parseCount(el.ChildText(parser.requestedSelector)); err == nil {
+			item.RequestedCount = count
+		}
+	}
+	if parser.purchasedSelector != "" {
+		if count, err := parseCount(el.ChildText(parser.purchasedSelector)); err == nil {
+			item.PurchasedCount = count
+		}
+	}
+	if note := strings.TrimSpace(el.ChildText(parser.noteSelector)); note != "" {
+		item.Comment = note
+	}
+}
@@ -0,0 +1,202 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemsIdeaList(t *testing.T) {
+	ts := newRegistryTestServer(t)
+	defer ts.Close()
+
+	wishlist, err := NewWishlist(ts.URL + "/ideas/IDEA123")
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	require.Equal(t, ListKindIdeaList, wishlist.Kind())
+
+	uris := wishlist.URLs()
+	require.Len(t, uris, 1)
+	require.Contains(t, uris[0], ts.URL+"/ideas/IDEA123?")
+	require.NotContains(t, uris[0], "reveal=")
+
+	name, err := wishlist.Name()
+	require.NoError(t, err)
+	require.Equal(t, "Cozy Reads", name)
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	item := items["0134190440"]
+	require.NotNil(t, item)
+	require.Equal(t, "The Go Programming Language", item.Name)
+	require.Equal(t, "0134190440", item.ASIN)
+	require.Equal(t, ts.URL+"/dp/0134190440", item.DirectURL)
+	require.Equal(t, "$32.99", item.Price)
+	require.Equal(t, "4.7 out of 5 stars", item.Rating)
+	require.Equal(t, "Great for learning the language.", item.Comment)
+	require.Equal(t, -1, item.RequestedCount)
+
+	item = items["B0018CLTKE"]
+	require.NotNil(t, item)
+	require.Equal(t, "Purina Tidy Cats Non-Clumping Cat Litter", item.Name)
+	require.Equal(t, "https://images-na.ssl-images-amazon.com/images/I/81YphWp9eIL._SS135_.jpg", item.ImageURL)
+}
+
+func TestItemsBabyRegistry(t *testing.T) {
+	ts := newRegistryTestServer(t)
+	defer ts.Close()
+
+	wishlist, err := NewWishlist(ts.URL + "/baby-reg/jane-doe-july-2020/BABY123")
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	require.Equal(t, ListKindBabyRegistry, wishlist.Kind())
+
+	name, err := wishlist.Name()
+	require.NoError(t, err)
+	require.Equal(t, "Jane Doe's Baby Registry", name)
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 2, "should follow the link to the next page")
+
+	item := items["BR1"]
+	require.NotNil(t, item)
+	require.Equal(t, "Swaddle Blankets, 3 Pack", item.Name)
+	require.Equal(t, "B01ABCDEF1", item.ASIN)
+	require.Equal(t, "$24.99", item.Price)
+	require.Equal(t, 2, item.RequestedCount)
+	require.Equal(t, 1, item.PurchasedCount)
+	require.Equal(t, "Any color but yellow", item.Comment)
+
+	item = items["BR2"]
+	require.NotNil(t, item)
+	require.Equal(t, "Baby Monitor", item.Name)
+	require.Equal(t, 1, item.RequestedCount)
+	require.Equal(t, 0, item.PurchasedCount)
+}
+
+func TestItemsWeddingRegistry(t *testing.T) {
+	ts := newRegistryTestServer(t)
+	defer ts.Close()
+
+	wishlist, err := NewWishlist(ts.URL + "/wedding/registry/WED123")
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	require.Equal(t, ListKindWeddingRegistry, wishlist.Kind())
+
+	name, err := wishlist.Name()
+	require.NoError(t, err)
+	require.Equal(t, "Jane & John's Wedding Registry", name)
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	item := items["WR1"]
+	require.NotNil(t, item)
+	require.Equal(t, "Stand Mixer", item.Name)
+	require.Equal(t, "B02XYZ1234", item.ASIN)
+	require.Equal(t, 4, item.RequestedCount)
+	require.Equal(t, 2, item.PurchasedCount)
+	require.True(t, item.IsPrime)
+}
+
+// The Idea List and registry pages below are hand-written, not recorded from
+// Amazon.
+const ideaListHTML = `<html>
+	<body>
+		<h1 id="idea-list-title">Cozy Reads</h1>
+		<div class="idea-list-item" data-asin="0134190440">
+			<a class="idea-list-item-link" href="/dp/0134190440">
+				<img src="https://images-na.ssl-images-amazon.com/images/I/41aSIZ+8gxL._SS135_.jpg">
+				<span class="idea-list-item-title">The Go Programming Language</span>
+			</a>
+			<span class="a-price"><span class="a-offscreen">$32.99</span></span>
+			<i class="a-icon a-icon-star-small"><span class="a-icon-alt">4.7 out of 5 stars</span></i>
+			<p class="idea-list-item-note">Great for learning the language.</p>
+		</div>
+		<div class="idea-list-item" data-asin="B0018CLTKE">
+			<a class="idea-list-item-link" href="/dp/B0018CLTKE" title="Purina Tidy Cats Non-Clumping Cat Litter">
+				<img src="https://images-na.ssl-images-amazon.com/images/I/81YphWp9eIL._SS135_.jpg">
+			</a>
+		</div>
+	</body>
+</html>`
+
+const babyRegistryHTML = `<html>
+	<body>
+		<h1 id="br-registry-name">Jane Doe's Baby Registry</h1>
+		<div class="br-item" data-itemid="BR1" data-asin="B01ABCDEF1">
+			<img class="br-item-image" src="https://images-na.ssl-images-amazon.com/images/I/swaddle.jpg">
+			<a class="br-item-title" href="/dp/B01ABCDEF1">Swaddle Blankets, 3 Pack</a>
+			<span class="a-price"><span class="a-offscreen">$24.99</span></span>
+			<span class="br-item-requested">Requested: 2</span>
+			<span class="br-item-purchased">Purchased: 1</span>
+			<span class="br-item-note">Any color but yellow</span>
+		</div>
+		<ul class="a-pagination">
+			<li class="a-last"><a href="/baby-reg/jane-doe-july-2020/BABY123?page=2">Next</a></li>
+		</ul>
+	</body>
+</html>`
+
+const babyRegistryPage2HTML = `<html>
+	<body>
+		<h1 id="br-registry-name">Jane Doe's Baby Registry</h1>
+		<div class="br-item" data-itemid="BR2" data-asin="B01ABCDEF2">
+			<a class="br-item-title" href="/dp/B01ABCDEF2">Baby Monitor</a>
+			<span class="br-item-requested">Requested: 1</span>
+			<span class="br-item-purchased">Purchased: 0</span>
+		</div>
+		<ul class="a-pagination">
+			<li class="a-last a-disabled">Next</li>
+		</ul>
+	</body>
+</html>`
+
+const weddingRegistryHTML = `<html>
+	<body>
+		<h1 id="wr-registry-name">Jane &amp; John's Wedding Registry</h1>
+		<div class="wr-item" data-itemid="WR1" data-asin="B02XYZ1234">
+			<a class="wr-item-title" href="/dp/B02XYZ1234">Stand Mixer</a>
+			<i class="a-icon a-icon-prime"></i>
+			<span class="wr-item-requested">Wants 4</span>
+			<span class="wr-item-received">Has 2</span>
+		</div>
+	</body>
+</html>`
+
+// newRegistryTestServer serves an Idea List, a two-page Baby Registry, and a
+// Wedding Registry. The Baby Registry's second page is only served when asked
+// for with "?page=2".
+func newRegistryTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/ideas/IDEA123", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(ideaListHTML))
+	})
+	babyRegistry := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Write([]byte(babyRegistryHTML))
+		case "2":
+			w.Write([]byte(babyRegistryPage2HTML))
+		default:
+			http.NotFound(w, r)
+		}
+	}
+	mux.HandleFunc("/baby-reg/BABY123", babyRegistry)
+	mux.HandleFunc("/baby-reg/jane-doe-july-2020/BABY123", babyRegistry)
+	mux.HandleFunc("/wedding/registry/WED123", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(weddingRegistryHTML))
+	})
+
+	return httptest.NewServer(mux)
+}
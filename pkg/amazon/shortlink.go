@@ -0,0 +1,84 @@
+package amazon
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	maxShortLinkRedirects = 10
+	shortLinkTimeout      = 30 * time.Second
+)
+
+var (
+	// shortLinkHosts are the hosts of Amazon's link shorteners, as used when
+	// sharing a list from the Amazon app.
+	shortLinkHosts = []string{"a.co", "amzn.to", "amzn.com", "amzn.eu", "amzn.asia"}
+)
+
+// IsShortLink returns true if the given URL is an Amazon short link, e.g.,
+// "https://a.co/d/abc123" or "https://amzn.to/2XyZabc".
+func IsShortLink(urlStr string) bool {
+	uri, err := url.Parse(strings.TrimSpace(urlStr))
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(uri.Hostname())
+	for _, shortLinkHost := range shortLinkHosts {
+		if host == shortLinkHost || host == "www."+shortLinkHost {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveShortLink follows the redirects from an Amazon short link until they
+// lead to a wishlist, and returns the wishlist's URL. The wishlist itself is
+// not requested. A link that is already to a wishlist is returned as is, and
+// any other link that is not a short link is an error; neither is requested.
+// If client is nil, a client with a default timeout is used.
+func ResolveShortLink(client *http.Client, urlStr string) (string, error) {
+	urlStr = strings.TrimSpace(urlStr)
+	if _, err := ParseWishlistURL(urlStr); err == nil {
+		return urlStr, nil
+	}
+	if !IsShortLink(urlStr) {
+		return "", fmt.Errorf("URL '%s' is not an Amazon short link", urlStr)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: shortLinkTimeout}
+	}
+
+	var wishlistURL string
+	resolver := *client
+	resolver.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if _, err := ParseWishlistURL(req.URL.String()); err == nil {
+			wishlistURL = req.URL.String()
+			return http.ErrUseLastResponse
+		}
+		if len(via) >= maxShortLinkRedirects {
+			return fmt.Errorf("Stopped after %d redirects from short link '%s'",
+				maxShortLinkRedirects, urlStr)
+		}
+		return nil
+	}
+
+	resp, err := resolver.Get(urlStr)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if wishlistURL == "" {
+		return "", fmt.Errorf("Short link '%s' does not lead to an Amazon wishlist",
+			urlStr)
+	}
+
+	return wishlistURL, nil
+}
@@ -0,0 +1,110 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsShortLink(t *testing.T) {
+	require.True(t, IsShortLink("https://a.co/d/abc123"))
+	require.True(t, IsShortLink("https://amzn.to/2XyZabc"))
+	require.True(t, IsShortLink("http://AMZN.EU/d/abc123"))
+	require.False(t, IsShortLink("https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT"))
+	require.False(t, IsShortLink("https://data.co/d/abc123"))
+}
+
+func TestResolveShortLink(t *testing.T) {
+	wishlistRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/d/abc123", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://www.amazon.com/gp/r.html?r=xyz", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/gp/r.html", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/hz/wishlist/ls/123abc?ref_=wl_share", http.StatusFound)
+	})
+	mux.HandleFunc("/hz/wishlist/ls/123abc", func(w http.ResponseWriter, r *http.Request) {
+		wishlistRequests++
+		w.Write([]byte(wishlistHTML))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	wishlistURL, err := ResolveShortLink(newShortLinkTestClient(ts), "https://a.co/d/abc123")
+	require.NoError(t, err)
+	require.Equal(t, "https://www.amazon.com/hz/wishlist/ls/123abc?ref_=wl_share", wishlistURL)
+	require.Equal(t, 0, wishlistRequests, "should not download the wishlist")
+}
+
+func TestResolveShortLinkNotToWishlist(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/d/abc123", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://www.amazon.com/dp/B0018CLTKE", http.StatusFound)
+	})
+	mux.HandleFunc("/dp/B0018CLTKE", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	_, err := ResolveShortLink(newShortLinkTestClient(ts), "https://a.co/d/abc123")
+	require.Error(t, err)
+}
+
+func TestResolveShortLinkWithoutRequests(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+	client := newShortLinkTestClient(ts)
+
+	wishlistURL, err := ResolveShortLink(client, " https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT ")
+	require.NoError(t, err)
+	require.Equal(t, "https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT", wishlistURL)
+
+	_, err = ResolveShortLink(client, "https://www.amazon.com/dp/B0018CLTKE")
+	require.Error(t, err)
+
+	require.Equal(t, 0, requests)
+}
+
+func TestNewWishlistFromShortLink(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://www.amazon.co.uk/hz/wishlist/ls/123abc", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromShortLink(newShortLinkTestClient(ts), "https://amzn.eu/d/abc123")
+	require.NoError(t, err)
+	require.Equal(t, "123abc", wishlist.ID())
+	require.Contains(t, wishlist.URLs()[0], "https://www.amazon.co.uk/hz/wishlist/ls/123abc")
+}
+
+func TestNewWishlistDoesNotFollowShortLink(t *testing.T) {
+	_, err := NewWishlist("https://a.co/d/abc123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "NewWishlistFromShortLink")
+}
+
+// newShortLinkTestClient returns a client that sends every request, whatever
+// its host, to the test server, so real short links can be resolved in tests.
+func newShortLinkTestClient(ts *httptest.Server) *http.Client {
+	client := ts.Client()
+	transport := client.Transport
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		r.URL.Scheme = "http"
+		r.URL.Host = ts.Listener.Addr().String()
+		return transport.RoundTrip(r)
+	})
+	return client
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,306 @@
+package amazon
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column is a column of a spreadsheet exported from a wishlist snapshot.
+type Column int
+
+const (
+	// ColumnName is the name of the product.
+	ColumnName Column = iota
+
+	// ColumnPrice is the price of the product, as a number.
+	ColumnPrice
+
+	// ColumnCurrency is the ISO 4217 code of the price's currency.
+	ColumnCurrency
+
+	// ColumnRequested is how many of the product the recipient would like.
+	ColumnRequested
+
+	// ColumnOwned is how many of the product the recipient already has.
+	ColumnOwned
+
+	// ColumnPriority is how much the recipient wants the product.
+	ColumnPriority
+
+	// ColumnDateAdded is when the product was added to the wishlist.
+	ColumnDateAdded
+
+	// ColumnRating is the product's average rating, in stars out of 5.
+	ColumnRating
+
+	// ColumnURL is the canonical URL of the product on Amazon.
+	ColumnURL
+
+	// ColumnImage is the URL of an image of the product.
+	ColumnImage
+
+	// ColumnLink is the name of the product, linked to its page on Amazon.
+	ColumnLink
+)
+
+var (
+	columnCodes = map[Column]string{
+		ColumnName:      "name",
+		ColumnPrice:     "price",
+		ColumnCurrency:  "currency",
+		ColumnRequested: "requested",
+		ColumnOwned:     "owned",
+		ColumnPriority:  "priority",
+		ColumnDateAdded: "date_added",
+		ColumnRating:    "rating",
+		ColumnURL:       "url",
+		ColumnImage:     "image",
+		ColumnLink:      "link",
+	}
+
+	columnHeaders = map[Column]string{
+		ColumnName:      "Name",
+		ColumnPrice:     "Price",
+		ColumnCurrency:  "Currency",
+		ColumnRequested: "Requested",
+		ColumnOwned:     "Owned",
+		ColumnPriority:  "Priority",
+		ColumnDateAdded: "Date Added",
+		ColumnRating:    "Rating",
+		ColumnURL:       "URL",
+		ColumnImage:     "Image",
+		ColumnLink:      "Link",
+	}
+
+	// commaDecimalLanguages write numbers with a decimal comma, e.g., "15,96".
+	commaDecimalLanguages = map[string]bool{
+		"cs": true, "da": true, "de": true, "es": true, "fi": true, "fr": true,
+		"it": true, "nb": true, "nl": true, "pl": true, "pt": true, "ru": true,
+		"sv": true, "tr": true,
+	}
+
+	// zeroDecimalCurrencies have no minor unit, so prices are whole numbers.
+	zeroDecimalCurrencies = map[string]bool{"JPY": true, "KRW": true}
+)
+
+// SpreadsheetOptions configure how a wishlist snapshot is exported.
+type SpreadsheetOptions struct {
+	// Columns are the columns to include, in order. If empty,
+	// DefaultColumns is used.
+	Columns []Column
+
+	// Language is the BCP 47 tag of the language whose conventions numbers
+	// are written in, e.g., "de-DE" for "15,96". If empty, the language of
+	// the snapshot's marketplace is used. XLSX files store numbers as
+	// numbers, which spreadsheet apps display in their own locale.
+	Language string
+}
+
+// spreadsheetCell is a value in an exported spreadsheet, with the text to
+// write to CSV files and the plain text or typed value for XLSX files.
+type spreadsheetCell struct {
+	text     string
+	plain    string
+	number   *float64
+	date     *time.Time
+	link     string
+	decimals int
+}
+
+// DefaultColumns returns the columns exported when none are specified.
+func DefaultColumns() []Column {
+	return []Column{ColumnName, ColumnPrice, ColumnCurrency, ColumnRequested,
+		ColumnOwned, ColumnPriority, ColumnDateAdded, ColumnRating, ColumnURL}
+}
+
+// ParseColumns reads a comma-separated list of column names, such as
+// "name,price,link", as accepted by cmd/getwishlist.
+func ParseColumns(names string) ([]Column, error) {
+	columns := []Column{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for column, code := range columnCodes {
+			if code == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Unknown column '%s'", name)
+		}
+	}
+	if len(columns) < 1 {
+		return nil, fmt.Errorf("No columns found in '%s'", names)
+	}
+	return columns, nil
+}
+
+// String returns the name of the column, as accepted by ParseColumns.
+func (c Column) String() string {
+	if code, ok := columnCodes[c]; ok {
+		return code
+	}
+	return "unknown"
+}
+
+// WriteCSV writes the snapshot's items as comma-separated values, with a
+// header row.
+func WriteCSV(w io.Writer, snapshot *Snapshot, options SpreadsheetOptions) error {
+	return writeDelimited(w, ',', snapshot, options)
+}
+
+// WriteTSV writes the snapshot's items as tab-separated values, with a header
+// row.
+func WriteTSV(w io.Writer, snapshot *Snapshot, options SpreadsheetOptions) error {
+	return writeDelimited(w, '\t', snapshot, options)
+}
+
+func writeDelimited(w io.Writer, delimiter rune, snapshot *Snapshot, options SpreadsheetOptions) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+
+	for _, row := range spreadsheetRows(snapshot, options) {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = cell.text
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// spreadsheetRows returns the header row and a row for each item.
+func spreadsheetRows(snapshot *Snapshot, options SpreadsheetOptions) [][]spreadsheetCell {
+	columns := options.Columns
+	if len(columns) < 1 {
+		columns = DefaultColumns()
+	}
+
+	language := options.Language
+	if language == "" && snapshot.Marketplace != nil {
+		language = snapshot.Marketplace.Language
+	}
+	decimalSeparator := "."
+	if commaDecimalLanguages[strings.ToLower(strings.SplitN(language, "-", 2)[0])] {
+		decimalSeparator = ","
+	}
+
+	header := make([]spreadsheetCell, len(columns))
+	for i, column := range columns {
+		header[i] = textCell(columnHeaders[column])
+	}
+
+	rows := [][]spreadsheetCell{header}
+	for _, item := range snapshot.Items {
+		row := make([]spreadsheetCell, len(columns))
+		for i, column := range columns {
+			row[i] = itemCell(item, column, decimalSeparator)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func itemCell(item *Item, column Column, decimalSeparator string) spreadsheetCell {
+	switch column {
+	case ColumnName:
+		return textCell(item.Name)
+	case ColumnPrice:
+		price, err := item.ParsedPrice()
+		if err != nil {
+			return spreadsheetCell{}
+		}
+		decimals := 2
+		if zeroDecimalCurrencies[price.Currency] {
+			decimals = 0
+		}
+		return numberCell(price.Amount, decimals, decimalSeparator)
+	case ColumnCurrency:
+		price, err := item.ParsedPrice()
+		if err != nil {
+			return spreadsheetCell{}
+		}
+		return textCell(price.Currency)
+	case ColumnRequested:
+		return countCell(item.RequestedCount)
+	case ColumnOwned:
+		return countCell(item.OwnedCount)
+	case ColumnPriority:
+		return textCell(item.Priority.String())
+	case ColumnDateAdded:
+		dateAdded, err := item.DateAdded()
+		if err != nil {
+			return spreadsheetCell{}
+		}
+		return spreadsheetCell{text: dateAdded.Format("2006-01-02"), date: dateAdded}
+	case ColumnRating:
+		stars, ok := item.Stars()
+		if !ok {
+			return spreadsheetCell{}
+		}
+		return numberCell(stars, 1, decimalSeparator)
+	case ColumnURL:
+		return textCell(item.CanonicalURL())
+	case ColumnImage:
+		return textCell(item.ImageURL)
+	case ColumnLink:
+		url := item.CanonicalURL()
+		if url == "" {
+			return textCell(item.Name)
+		}
+		// Spreadsheet apps in locales with a decimal comma separate formula
+		// arguments with semicolons.
+		argumentSeparator := ","
+		if decimalSeparator == "," {
+			argumentSeparator = ";"
+		}
+		formula := fmt.Sprintf(`=HYPERLINK("%s"%s"%s")`, escapeFormulaString(url),
+			argumentSeparator, escapeFormulaString(item.Name))
+		return spreadsheetCell{text: formula, plain: item.Name, link: url}
+	}
+	return spreadsheetCell{}
+}
+
+// textCell returns a cell with the given text. Text that a spreadsheet app
+// would run as a formula is prefixed with an apostrophe, since it comes from
+// Amazon pages that anyone can write on.
+func textCell(text string) spreadsheetCell {
+	if text != "" && strings.ContainsAny(text[:1], "=+-@") {
+		return spreadsheetCell{text: "'" + text, plain: text}
+	}
+	return spreadsheetCell{text: text, plain: text}
+}
+
+func numberCell(value float64, decimals int, decimalSeparator string) spreadsheetCell {
+	text := strconv.FormatFloat(value, 'f', decimals, 64)
+	if decimalSeparator != "." {
+		text = strings.Replace(text, ".", decimalSeparator, 1)
+	}
+	return spreadsheetCell{text: text, number: &value, decimals: decimals}
+}
+
+func countCell(count int) spreadsheetCell {
+	if count < 0 {
+		return spreadsheetCell{}
+	}
+	value := float64(count)
+	return spreadsheetCell{text: strconv.Itoa(count), number: &value}
+}
+
+func escapeFormulaString(text string) string {
+	return strings.Replace(text, `"`, `""`, -1)
+}
@@ -0,0 +1,119 @@
+package amazon
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSpreadsheetTestSnapshot() *Snapshot {
+	marketplace := marketplaceForHost("www.amazon.de")
+
+	litter := NewItem("I2G6UJO0FYWV8J", `Purina "Tidy Cats" Cat Litter`,
+		"https://www.amazon.de/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J")
+	litter.marketplace = marketplace
+	litter.Price = "15,96 €"
+	litter.RawDateAdded = "10. Juli 2019"
+	litter.Rating = "4,5 von 5 Sternen"
+	litter.RequestedCount = 2
+	litter.OwnedCount = 0
+	litter.Priority = PriorityHigh
+
+	sneaky := NewItem("I3TN2X0G8B7Q1K", "=1+2", "")
+	sneaky.marketplace = marketplace
+
+	return &Snapshot{
+		ID:          "123abc",
+		Marketplace: marketplace,
+		Metadata:    Metadata{Name: "Cats: [Supplies]"},
+		Items:       []*Item{litter, sneaky},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	snapshot := newSpreadsheetTestSnapshot()
+	options := SpreadsheetOptions{
+		Columns:  []Column{ColumnName, ColumnPrice, ColumnCurrency, ColumnRequested, ColumnDateAdded, ColumnRating, ColumnLink},
+		Language: "en-US",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, snapshot, options))
+	require.Equal(t, `Name,Price,Currency,Requested,Date Added,Rating,Link
+"Purina ""Tidy Cats"" Cat Litter",15.96,EUR,2,2019-07-10,4.5,"=HYPERLINK(""https://www.amazon.de/dp/B0018CLTKE"",""Purina """"Tidy Cats"""" Cat Litter"")"
+'=1+2,,,,,,'=1+2
+`, buf.String())
+}
+
+func TestWriteTSVMarketplaceLanguage(t *testing.T) {
+	snapshot := newSpreadsheetTestSnapshot()
+	options := SpreadsheetOptions{Columns: []Column{ColumnPrice, ColumnRating, ColumnPriority, ColumnLink}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTSV(&buf, snapshot, options))
+	lines := strings.Split(buf.String(), "\n")
+	require.Equal(t, "Price\tRating\tPriority\tLink", lines[0])
+	require.Equal(t, "15,96\t4,5\thigh\t"+
+		`"=HYPERLINK(""https://www.amazon.de/dp/B0018CLTKE"";""Purina """"Tidy Cats"""" Cat Litter"")"`,
+		lines[1])
+}
+
+func TestWriteXLSX(t *testing.T) {
+	snapshot := newSpreadsheetTestSnapshot()
+	options := SpreadsheetOptions{Columns: []Column{ColumnName, ColumnPrice, ColumnOwned, ColumnDateAdded, ColumnLink}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, snapshot, options))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		reader.Close()
+		parts[file.Name] = string(content)
+	}
+
+	require.Contains(t, parts, "[Content_Types].xml")
+	require.Contains(t, parts, "xl/styles.xml")
+	require.Contains(t, parts["xl/workbook.xml"], `<sheet name="Cats- (Supplies)"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	require.Contains(t, sheet, `<c r="A2" s="0" t="inlineStr"><is><t xml:space="preserve">Purina &#34;Tidy Cats&#34; Cat Litter</t></is></c>`)
+	require.Contains(t, sheet, `<c r="B2" s="2"><v>15.96</v></c>`)
+	require.Contains(t, sheet, `<c r="C2" s="1"><v>0</v></c>`)
+	require.Contains(t, sheet, `<c r="D2" s="3"><v>43656</v></c>`)
+	require.Contains(t, sheet, `<t xml:space="preserve">=1+2</t>`)
+	require.NotContains(t, sheet, `r="C3"`)
+	require.Contains(t, sheet, `<hyperlink ref="E2" r:id="rId1"/>`)
+	require.NotContains(t, sheet, `<hyperlink ref="E3"`)
+	require.Contains(t, parts["xl/worksheets/_rels/sheet1.xml.rels"],
+		`Target="https://www.amazon.de/dp/B0018CLTKE" TargetMode="External"`)
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns("name, Price,link")
+	require.NoError(t, err)
+	require.Equal(t, []Column{ColumnName, ColumnPrice, ColumnLink}, columns)
+	require.Equal(t, "date_added", ColumnDateAdded.String())
+
+	_, err = ParseColumns("name,shipping")
+	require.Error(t, err)
+
+	_, err = ParseColumns(" , ")
+	require.Error(t, err)
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	require.Equal(t, "A", xlsxColumnName(0))
+	require.Equal(t, "Z", xlsxColumnName(25))
+	require.Equal(t, "AA", xlsxColumnName(26))
+	require.Equal(t, "BA", xlsxColumnName(52))
+}
@@ -0,0 +1,181 @@
+package amazon
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	associateTagParam = "tag"
+)
+
+var (
+	asinRegexp    = regexp.MustCompile(`/(?:dp|gp/product|product-reviews|gp/offer-listing)/([A-Z0-9]{10})(?:[/?]|$)`)
+	refPathRegexp = regexp.MustCompile(`/ref=[^/]*`)
+
+	// trackingParams are query parameters Amazon adds to links to record where
+	// a click came from. They are not needed to find the product.
+	trackingParams = []string{"ref", "ref_", "qid", "sr", "_encoding", "linkCode",
+		"linkId", "content-id", "ascsubtag", "creative", "creativeASIN",
+		associateTagParam}
+
+	// trackingParamPrefixes are prefixes of tracking query parameters, e.g.,
+	// "pd_rd_w" and "pf_rd_p".
+	trackingParamPrefixes = []string{"pd_rd_", "pf_rd_"}
+)
+
+// WishlistURL returns the canonical URL of the wishlist with the given ID on
+// the Amazon site at baseURL, e.g., "https://www.amazon.com", without any
+// tracking parameters.
+func WishlistURL(baseURL string, id string) string {
+	return fmt.Sprintf("%s/hz/wishlist/ls/%s", trimBaseURL(baseURL), url.PathEscape(id))
+}
+
+// ListURL returns the canonical URL of the list of the given kind with the
+// given ID on the Amazon site at baseURL.
+func ListURL(baseURL string, kind ListKind, id string) string {
+	switch kind {
+	case ListKindIdeaList:
+		return fmt.Sprintf("%s/ideas/%s", trimBaseURL(baseURL), url.PathEscape(id))
+	case ListKindBabyRegistry:
+		return fmt.Sprintf("%s/baby-reg/%s", trimBaseURL(baseURL), url.PathEscape(id))
+	case ListKindWeddingRegistry:
+		return fmt.Sprintf("%s/wedding/registry/%s", trimBaseURL(baseURL),
+			url.PathEscape(id))
+	}
+	return WishlistURL(baseURL, id)
+}
+
+// PrintViewURL returns the URL of the printer-friendly view of the list of the
+// given kind with the given ID on the Amazon site at baseURL. Only wishlists
+// have a printer-friendly view, so an empty string is returned for other kinds.
+func PrintViewURL(baseURL string, kind ListKind, id string) string {
+	if kind != ListKindWishlist {
+		return ""
+	}
+	return fmt.Sprintf("%s/hz/wishlist/printview/%s", trimBaseURL(baseURL),
+		url.PathEscape(id))
+}
+
+// ProductURL returns the canonical URL of the product with the given ASIN on
+// the Amazon site at baseURL. If associateTag is not empty, it is included so
+// purchases are credited to that Amazon Associates account.
+func ProductURL(baseURL string, asin string, associateTag string) string {
+	productURL := fmt.Sprintf("%s/dp/%s", trimBaseURL(baseURL), url.PathEscape(asin))
+	return WithAssociateTag(productURL, associateTag)
+}
+
+// ReviewsURL returns the URL of the customer reviews of the product with the
+// given ASIN on the Amazon site at baseURL.
+func ReviewsURL(baseURL string, asin string) string {
+	return fmt.Sprintf("%s/product-reviews/%s", trimBaseURL(baseURL),
+		url.PathEscape(asin))
+}
+
+// OfferListingURL returns the URL listing all the offers from different
+// sellers for the product with the given ASIN on the Amazon site at baseURL.
+func OfferListingURL(baseURL string, asin string, associateTag string) string {
+	offersURL := fmt.Sprintf("%s/gp/offer-listing/%s", trimBaseURL(baseURL),
+		url.PathEscape(asin))
+	return WithAssociateTag(offersURL, associateTag)
+}
+
+// WithAssociateTag returns the given URL with the Amazon Associates tag set,
+// replacing any existing tag. The URL is returned unchanged if the tag is
+// empty or the URL cannot be parsed.
+func WithAssociateTag(urlStr string, associateTag string) string {
+	if associateTag == "" {
+		return urlStr
+	}
+
+	uri, err := url.Parse(urlStr)
+	if err != nil {
+		return urlStr
+	}
+
+	query := uri.Query()
+	query.Set(associateTagParam, associateTag)
+	uri.RawQuery = query.Encode()
+
+	return uri.String()
+}
+
+// withoutTracking returns the given URL without Amazon's tracking parameters
+// and "/ref=" path segments, and with the Amazon Associates tag set if it is
+// not empty. Other query parameters are kept in their original order. The URL
+// is returned unchanged if it cannot be parsed.
+func withoutTracking(urlStr string, associateTag string) string {
+	uri, err := url.Parse(urlStr)
+	if err != nil {
+		return urlStr
+	}
+
+	uri.Path = refPathRegexp.ReplaceAllString(uri.Path, "")
+	uri.RawPath = ""
+
+	var params []string
+	for _, param := range strings.Split(uri.RawQuery, "&") {
+		if param == "" {
+			continue
+		}
+		key := strings.SplitN(param, "=", 2)[0]
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if !isTrackingParam(key) {
+			params = append(params, param)
+		}
+	}
+	if associateTag != "" {
+		params = append(params, associateTagParam+"="+url.QueryEscape(associateTag))
+	}
+	uri.RawQuery = strings.Join(params, "&")
+
+	return uri.String()
+}
+
+func isTrackingParam(key string) bool {
+	for _, param := range trackingParams {
+		if key == param {
+			return true
+		}
+	}
+	for _, prefix := range trackingParamPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ASINFromURL returns the Amazon Standard Identification Number of the product
+// in the given URL, e.g., "B0018CLTKE" for ".../dp/B0018CLTKE/?coliid=...".
+// Returns an empty string if the URL is not to a product.
+func ASINFromURL(urlStr string) string {
+	matches := asinRegexp.FindStringSubmatch(urlStr)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// baseURLFromURL returns the scheme, host, and port of the given URL, e.g.,
+// "https://www.amazon.com" for "https://www.amazon.com/dp/B0018CLTKE".
+func baseURLFromURL(urlStr string) (string, error) {
+	uri, err := url.Parse(urlStr)
+	if err != nil {
+		return "", err
+	}
+
+	if uri.Scheme == "" || uri.Host == "" {
+		return "", fmt.Errorf("URL '%s' is not an absolute URL", urlStr)
+	}
+
+	return fmt.Sprintf("%s://%s", uri.Scheme, uri.Host), nil
+}
+
+func trimBaseURL(baseURL string) string {
+	return strings.TrimRight(baseURL, "/")
+}
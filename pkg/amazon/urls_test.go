@@ -0,0 +1,90 @@
+package amazon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLBuilders(t *testing.T) {
+	baseURL := "https://www.amazon.de/"
+
+	require.Equal(t, "https://www.amazon.de/hz/wishlist/ls/3I6EQPZ8OB1DT",
+		WishlistURL(baseURL, "3I6EQPZ8OB1DT"))
+	require.Equal(t, "https://www.amazon.de/hz/wishlist/ls/3I6EQPZ8OB1DT",
+		ListURL(baseURL, ListKindWishlist, "3I6EQPZ8OB1DT"))
+	require.Equal(t, "https://www.amazon.de/ideas/3I6EQPZ8OB1DT",
+		ListURL(baseURL, ListKindIdeaList, "3I6EQPZ8OB1DT"))
+	require.Equal(t, "https://www.amazon.de/baby-reg/3I6EQPZ8OB1DT",
+		ListURL(baseURL, ListKindBabyRegistry, "3I6EQPZ8OB1DT"))
+	require.Equal(t, "https://www.amazon.de/wedding/registry/3I6EQPZ8OB1DT",
+		ListURL(baseURL, ListKindWeddingRegistry, "3I6EQPZ8OB1DT"))
+	require.Equal(t, "https://www.amazon.de/hz/wishlist/printview/3I6EQPZ8OB1DT",
+		PrintViewURL(baseURL, ListKindWishlist, "3I6EQPZ8OB1DT"))
+	require.Equal(t, "", PrintViewURL(baseURL, ListKindBabyRegistry, "BABY123"))
+	require.Equal(t, "https://www.amazon.de/dp/B0018CLTKE",
+		ProductURL(baseURL, "B0018CLTKE", ""))
+	require.Equal(t, "https://www.amazon.de/dp/B0018CLTKE?tag=mysite-21",
+		ProductURL(baseURL, "B0018CLTKE", "mysite-21"))
+	require.Equal(t, "https://www.amazon.de/product-reviews/B0018CLTKE",
+		ReviewsURL(baseURL, "B0018CLTKE"))
+	require.Equal(t, "https://www.amazon.de/gp/offer-listing/B0018CLTKE",
+		OfferListingURL(baseURL, "B0018CLTKE", ""))
+}
+
+func TestWithAssociateTag(t *testing.T) {
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE?psc=1&tag=mysite-20",
+		WithAssociateTag("https://www.amazon.com/dp/B0018CLTKE?psc=1&tag=other-20", "mysite-20"))
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE",
+		WithAssociateTag("https://www.amazon.com/dp/B0018CLTKE", ""))
+}
+
+func TestASINFromURL(t *testing.T) {
+	tests := map[string]string{
+		"https://www.amazon.com/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&colid=3I6EQPZ8OB1DT&psc=1&ref_=lv_vv_lig_dp_it": "B0018CLTKE",
+		"https://www.amazon.com/dp/B0018CLTKE":                        "B0018CLTKE",
+		"https://www.amazon.com/Tidy-Cats-Litter/dp/B0018CLTKE?psc=1": "B0018CLTKE",
+		"https://www.amazon.com/gp/product/1234567890/ref=ppx_od_dt":  "1234567890",
+		"/product-reviews/B0018CLTKE/?colid=3I6EQPZ8OB1DT":            "B0018CLTKE",
+		"/gp/offer-listing/B0018CLTKE/?colid=3I6EQPZ8OB1DT":           "B0018CLTKE",
+		"https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT":         "",
+		"https://www.amazon.com/dp/B0018CLTKEXYZ":                     "",
+	}
+
+	for url, expected := range tests {
+		require.Equal(t, expected, ASINFromURL(url), url)
+	}
+}
+
+func TestWithoutTracking(t *testing.T) {
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&colid=3I6EQPZ8OB1DT&psc=1",
+		withoutTracking("https://www.amazon.com/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&colid=3I6EQPZ8OB1DT&psc=1&ref_=lv_vv_lig_dp_it", ""))
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE?psc=1&tag=mysite-20",
+		withoutTracking("https://www.amazon.com/dp/B0018CLTKE/ref=sr_1_1?pd_rd_w=abc&psc=1&qid=123&tag=other-20", "mysite-20"))
+	require.Equal(t, "https://www.amazon.com/gp/item-dispatch?registryID.1=ABC&submit.addToCart=1",
+		withoutTracking("https://www.amazon.com/gp/item-dispatch?registryID.1=ABC&submit.addToCart=1&ref_=lv_vv_lig_pab", ""))
+}
+
+func TestItemCanonicalURLs(t *testing.T) {
+	item := NewItem("I2G6UJO0FYWV8J", "Cat Litter",
+		"https://www.amazon.com/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&colid=3I6EQPZ8OB1DT&psc=1&ref_=lv_vv_lig_dp_it")
+	require.Equal(t, "B0018CLTKE", item.ASIN)
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE", item.CanonicalURL())
+	require.Equal(t, "https://www.amazon.com/product-reviews/B0018CLTKE", item.CanonicalReviewsURL())
+	require.Equal(t, "https://www.amazon.com/gp/offer-listing/B0018CLTKE", item.OfferListingURL())
+
+	item.associateTag = "mysite-20"
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE?tag=mysite-20", item.CanonicalURL())
+
+	item.ASIN = ""
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&colid=3I6EQPZ8OB1DT&psc=1&tag=mysite-20",
+		item.CanonicalURL())
+	require.Equal(t, "", item.CanonicalAddToCartURL())
+}
+
+func TestWishlistCanonicalURLs(t *testing.T) {
+	wishlist, err := NewWishlist("https://smile.amazon.com/gp/registry/wishlist/3I6EQPZ8OB1DT/ref=cm_wl_huc_view")
+	require.NoError(t, err)
+	require.Equal(t, "https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT", wishlist.CanonicalURL())
+	require.Equal(t, "https://www.amazon.com/hz/wishlist/printview/3I6EQPZ8OB1DT", wishlist.PrintViewURL())
+}
@@ -1,14 +1,40 @@
 package amazon
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 )
 
+// TestMain caches responses in a temporary directory, rather than next to the
+// tests, for tests that load pages with the default settings.
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "amazon-cache")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	cachePath = dir
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// clearCache forgets the responses cached by earlier tests, whose test servers
+// may have had the same address as the next one.
+func clearCache(t *testing.T) {
+	require.NoError(t, os.RemoveAll(cachePath))
+}
+
 func TestNewWishlist(t *testing.T) {
 	id := "123abc"
 	ts := newTestServer(t, id)
@@ -101,19 +127,133 @@ func TestItems(t *testing.T) {
 	require.Equal(t, "July 10, 2019", item.RawDateAdded)
 	dateAdded, err := item.DateAdded()
 	require.NoError(t, err)
-	expectedDateAdded := time.Date(2019, 7, 10, 0, 0, 0, 0, time.UTC)
+	location, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	expectedDateAdded := time.Date(2019, 7, 10, 0, 0, 0, 0, location)
 	require.Equal(t, &expectedDateAdded, dateAdded)
 	require.Equal(t, "https://images-na.ssl-images-amazon.com/images/I/81YphWp9eIL._SS135_.jpg", item.ImageURL)
 	require.Equal(t, 50, item.RequestedCount)
 	require.Equal(t, 11, item.OwnedCount)
+	require.Equal(t, PriorityMedium, item.Priority)
 	require.Equal(t, "4.0 out of 5 stars", item.Rating)
 	require.Equal(t, 930, item.ReviewCount)
+	stars, ok := item.Stars()
+	require.True(t, ok)
+	require.Equal(t, 4.0, stars)
 	require.Equal(t, ts.URL+"/product-reviews/B0018CLTKE/?colid=3I6EQPZ8OB1DT&coliid=I2G6UJO0FYWV8J&showViewpoints=1&ref_=lv_vv_lig_pr_rc", item.ReviewsURL)
 	require.True(t, item.IsPrime, "should be marked as a Prime item")
+	require.Equal(t, "", item.Byline)
+	require.Equal(t, AvailabilityUnknown, item.Availability)
 	require.NotEqual(t, "", item.AddToCartURL)
 	require.Contains(t, item.AddToCartURL, ts.URL)
 	require.Contains(t, item.AddToCartURL, itemID)
 	require.Equal(t, ts.URL+"/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&colid=3I6EQPZ8OB1DT&psc=1&ref_=lv_vv_lig_dp_it", item.DirectURL)
+	require.Equal(t, "B0018CLTKE", item.ASIN)
+	require.Equal(t, ts.URL+"/dp/B0018CLTKE", item.CanonicalURL())
+}
+
+func TestItemsWithAssociateTag(t *testing.T) {
+	id := "123abc"
+	ts := newTestServer(t, id)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.AssociateTag = "mysite-20"
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+
+	item := items["I2G6UJO0FYWV8J"]
+	require.NotNil(t, item)
+	require.Equal(t, ts.URL+"/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J&colid=3I6EQPZ8OB1DT&psc=1&ref_=lv_vv_lig_dp_it", item.DirectURL,
+		"should keep the scraped URL")
+	require.Equal(t, ts.URL+"/dp/B0018CLTKE?tag=mysite-20", item.CanonicalURL())
+	require.NotContains(t, item.AddToCartURL, "tag=")
+	addToCartURL := item.CanonicalAddToCartURL()
+	require.True(t, strings.HasSuffix(addToCartURL, "&quantity.1=1&tag=mysite-20"), addToCartURL)
+	require.NotContains(t, addToCartURL, "ref_=")
+}
+
+func TestItemsRobotPage(t *testing.T) {
+	id := "123abc"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<form method="get" action="/errors/validateCaptcha"><input id="captchacharacters"></form>`))
+	}))
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	_, err = wishlist.Items()
+	require.Equal(t, ErrRobot, err)
+}
+
+func TestItemsAfterFailedLoad(t *testing.T) {
+	id := "123abc"
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/html")
+		if requests == 1 {
+			w.Write([]byte(`<form method="get" action="/errors/validateCaptcha"></form>`))
+			return
+		}
+		w.Write([]byte(wishlistHTML))
+	}))
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	_, err = wishlist.Name()
+	require.Equal(t, ErrRobot, err)
+
+	items, err := wishlist.Items()
+	require.NoError(t, err, "should not fail because of the earlier load")
+	require.Len(t, items, 1)
+	require.Empty(t, wishlist.Errors())
+}
+
+func TestItemsSignInRequired(t *testing.T) {
+	id := "123abc"
+	ts := newPrivateTestServer(t, id, nil)
+	defer ts.Close()
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+
+	_, err = wishlist.Items()
+	require.Equal(t, ErrSignInRequired, err)
+}
+
+func TestItemsWithCookieJar(t *testing.T) {
+	id := "123abc"
+	cookieHeaders := make(chan string, 1)
+	ts := newPrivateTestServer(t, id, cookieHeaders)
+	defer ts.Close()
+
+	uri, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	jar, err := ReadCookies(strings.NewReader(fmt.Sprintf(
+		"# Netscape HTTP Cookie File\n%s\tFALSE\t/\tFALSE\t0\tsession-id\t123-4567890\n",
+		uri.Hostname())))
+	require.NoError(t, err)
+
+	wishlist, err := NewWishlistFromIDAtDomain(id, ts.URL)
+	require.NoError(t, err)
+	wishlist.CacheResults = false
+	wishlist.SetCookieJar(jar)
+
+	items, err := wishlist.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Contains(t, <-cookieHeaders, "i18n-prefs=USD")
 }
 
 const wishlistHTML = `<!doctype html>
@@ -231,3 +371,31 @@ func newTestServer(t *testing.T, wishlistID string) *httptest.Server {
 
 	return httptest.NewServer(mux)
 }
+
+// newPrivateTestServer serves a wishlist only to requests with a session
+// cookie, redirecting others to the sign-in page like Amazon does for private
+// lists. The Cookie header of each request that is served the wishlist is sent
+// to cookieHeaders, if there's room.
+func newPrivateTestServer(t *testing.T, wishlistID string, cookieHeaders chan<- string) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/hz/wishlist/ls/"+wishlistID, func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session-id"); err != nil {
+			http.Redirect(w, r, "/ap/signin?openid.return_to="+url.QueryEscape(r.URL.String()),
+				http.StatusFound)
+			return
+		}
+		select {
+		case cookieHeaders <- r.Header.Get("Cookie"):
+		default:
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(wishlistHTML))
+	})
+	mux.HandleFunc("/ap/signin", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<form name="signIn" method="post"><input type="email" name="email"></form>`))
+	})
+
+	return httptest.NewServer(mux)
+}
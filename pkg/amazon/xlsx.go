@@ -0,0 +1,226 @@
+package amazon
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	xlsxMaxSheetNameLength = 31
+	xlsxDefaultSheetName   = "Wishlist"
+
+	// Styles defined in xlsxStyles, by index.
+	xlsxStyleDefault  = 0
+	xlsxStyleWhole    = 1
+	xlsxStyleDecimals = 2
+	xlsxStyleDate     = 3
+	xlsxStyleRating   = 4
+	xlsxStyleLink     = 5
+
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+	// xlsxStyles uses built-in number formats where they exist, so dates are
+	// shown the way the spreadsheet app's locale writes them.
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="0.0"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><u/><sz val="11"/><color rgb="FF0563C1"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="6">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="1" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+</cellXfs>
+</styleSheet>`
+)
+
+var (
+	// xlsxEpoch is day 0 of the dates spreadsheet apps store as numbers.
+	xlsxEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+	xlsxSheetNameReplacer = strings.NewReplacer("[", "(", "]", ")", ":", "-", "*", "",
+		"?", "", "/", "-", "\\", "-")
+)
+
+// WriteXLSX writes the snapshot's items as an Excel workbook with a header
+// row. Prices, counts, ratings, and dates are stored as numbers, and the
+// Link column holds real hyperlinks.
+func WriteXLSX(w io.Writer, snapshot *Snapshot, options SpreadsheetOptions) error {
+	archive := zip.NewWriter(w)
+
+	rows := spreadsheetRows(snapshot, options)
+	sheet, links := xlsxSheet(rows)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(snapshot.Metadata.Name)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", sheet},
+	}
+	if len(links) > 0 {
+		files = append(files, struct {
+			name    string
+			content string
+		}{"xl/worksheets/_rels/sheet1.xml.rels", xlsxSheetRels(links)})
+	}
+
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(writer, file.content); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func xlsxWorkbook(name string) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="`)
+	xmlEscape(&sb, xlsxSheetName(name))
+	sb.WriteString(`" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`)
+	return sb.String()
+}
+
+// xlsxSheet returns the worksheet XML for the given rows, and the URLs of the
+// hyperlinks in it, in the order of their relationship IDs.
+func xlsxSheet(rows [][]spreadsheetCell) (string, []string) {
+	var sb strings.Builder
+	var hyperlinks strings.Builder
+	links := []string{}
+
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheetData>`)
+	for rowIndex, row := range rows {
+		rowNumber := rowIndex + 1
+		fmt.Fprintf(&sb, `<row r="%d">`, rowNumber)
+		for columnIndex, cell := range row {
+			ref := xlsxColumnName(columnIndex) + strconv.Itoa(rowNumber)
+			xlsxCell(&sb, ref, cell)
+
+			if cell.link != "" {
+				links = append(links, cell.link)
+				fmt.Fprintf(&hyperlinks, `<hyperlink ref="%s" r:id="rId%d"/>`, ref, len(links))
+			}
+		}
+		sb.WriteString("</row>")
+	}
+	sb.WriteString("</sheetData>")
+
+	if len(links) > 0 {
+		sb.WriteString("<hyperlinks>")
+		sb.WriteString(hyperlinks.String())
+		sb.WriteString("</hyperlinks>")
+	}
+	sb.WriteString("</worksheet>")
+
+	return sb.String(), links
+}
+
+func xlsxCell(sb *strings.Builder, ref string, cell spreadsheetCell) {
+	switch {
+	case cell.date != nil:
+		year, month, day := cell.date.Date()
+		days := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Sub(xlsxEpoch).Hours() / 24
+		fmt.Fprintf(sb, `<c r="%s" s="%d"><v>%d</v></c>`, ref, xlsxStyleDate, int(days))
+	case cell.number != nil:
+		style := xlsxStyleWhole
+		if cell.decimals == 1 {
+			style = xlsxStyleRating
+		} else if cell.decimals > 1 {
+			style = xlsxStyleDecimals
+		}
+		fmt.Fprintf(sb, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style,
+			strconv.FormatFloat(*cell.number, 'f', -1, 64))
+	case cell.plain != "":
+		style := xlsxStyleDefault
+		if cell.link != "" {
+			style = xlsxStyleLink
+		}
+		fmt.Fprintf(sb, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+		xmlEscape(sb, cell.plain)
+		sb.WriteString("</t></is></c>")
+	}
+}
+
+func xlsxSheetRels(links []string) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, link := range links {
+		fmt.Fprintf(&sb, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="`, i+1)
+		xmlEscape(&sb, link)
+		sb.WriteString(`" TargetMode="External"/>`)
+	}
+	sb.WriteString("</Relationships>")
+	return sb.String()
+}
+
+// xlsxColumnName returns the letters naming the column at the given index,
+// e.g., "A" for 0 and "AA" for 26.
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetName returns a worksheet name Excel accepts for the given wishlist
+// name.
+func xlsxSheetName(name string) string {
+	name = strings.TrimSpace(xlsxSheetNameReplacer.Replace(name))
+	name = strings.Trim(name, "'")
+	if runes := []rune(name); len(runes) > xlsxMaxSheetNameLength {
+		name = string(runes[:xlsxMaxSheetNameLength])
+	}
+	if name == "" {
+		return xlsxDefaultSheetName
+	}
+	return name
+}
+
+func xmlEscape(sb *strings.Builder, text string) {
+	xml.EscapeText(sb, []byte(text))
+}
@@ -0,0 +1,132 @@
+// Package prices keeps the price history of items across snapshots of
+// wishlists, and notifies you when a rule says it's a good time to buy.
+package prices
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+// Point is an item's price and availability in one snapshot.
+type Point struct {
+	// Time is when the snapshot was taken.
+	Time time.Time
+
+	// Price is the parsed price, or nil if Amazon showed none.
+	Price *amazon.Price
+
+	// RawPrice is the price as Amazon wrote it, e.g., "$15.96".
+	RawPrice string
+
+	// Availability is whether the item could be bought.
+	Availability amazon.Availability
+}
+
+// History is the price of one item on one wishlist over time, oldest first.
+type History struct {
+	// WishlistID is the ID of the wishlist the item is on.
+	WishlistID string
+
+	// ItemID is the ID of the item on the wishlist.
+	ItemID string
+
+	// ASIN is the product's ID in the most recent snapshot, empty if unknown.
+	// A list can have the same product more than once, e.g., in different
+	// sizes, and each item has a history of its own.
+	ASIN string
+
+	// Name is the name of the product in the most recent snapshot.
+	Name string
+
+	// Points are the product's price and availability in each snapshot,
+	// oldest first.
+	Points []*Point
+}
+
+// historyID identifies a history in a Tracker. Each wishlist has histories
+// of its own, so the same product on two lists is tracked separately, and a
+// snapshot of one list is never compared with a snapshot of another.
+type historyID struct {
+	wishlistID string
+	itemID     string
+}
+
+// copy returns a copy of the history that shares none of its points.
+func (h *History) copy() *History {
+	points := make([]*Point, len(h.Points))
+	for i, point := range h.Points {
+		points[i] = point.copy()
+	}
+	return &History{WishlistID: h.WishlistID, ItemID: h.ItemID, ASIN: h.ASIN,
+		Name: h.Name, Points: points}
+}
+
+func newPoint(item *amazon.Item, t time.Time) *Point {
+	point := &Point{Time: t, RawPrice: item.Price, Availability: item.Availability}
+	if price, err := item.ParsedPrice(); err == nil {
+		point.Price = price
+	}
+	return point
+}
+
+func (p *Point) copy() *Point {
+	point := *p
+	if p.Price != nil {
+		price := *p.Price
+		point.Price = &price
+	}
+	return &point
+}
+
+// Latest returns the most recent point, or nil if there are none.
+func (h *History) Latest() *Point {
+	if len(h.Points) < 1 {
+		return nil
+	}
+	return h.Points[len(h.Points)-1]
+}
+
+// Lowest returns the point with the lowest price in the given currency, the
+// earliest if there's a tie, or nil if none has a price in that currency.
+func (h *History) Lowest(currency string) *Point {
+	var lowest *Point
+	for _, point := range h.Points {
+		if point.Price == nil || point.Price.Currency != currency {
+			continue
+		}
+		if lowest == nil || point.Price.Amount < lowest.Price.Amount {
+			lowest = point
+		}
+	}
+	return lowest
+}
+
+// add inserts the point in time order, replacing any point at the same time.
+// Returns true if the point is now the latest.
+func (h *History) add(point *Point) bool {
+	i := sort.Search(len(h.Points), func(i int) bool {
+		return !h.Points[i].Time.Before(point.Time)
+	})
+	if i < len(h.Points) && h.Points[i].Time.Equal(point.Time) {
+		h.Points[i] = point
+		return false
+	}
+
+	h.Points = append(h.Points, nil)
+	copy(h.Points[i+1:], h.Points[i:])
+	h.Points[i] = point
+	return i == len(h.Points)-1
+}
+
+// previousPriced returns the most recent point before the latest one with a
+// price in the given currency, or nil.
+func (h *History) previousPriced(currency string) *Point {
+	for i := len(h.Points) - 2; i >= 0; i-- {
+		if price := h.Points[i].Price; price != nil && price.Currency == currency {
+			return h.Points[i]
+		}
+	}
+	return nil
+}
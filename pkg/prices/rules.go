@@ -0,0 +1,187 @@
+package prices
+
+import (
+	"fmt"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+// EventKind is the reason a rule fired.
+type EventKind int
+
+const (
+	// EventPriceDrop means the price dropped by at least some percentage
+	// since the previous snapshot.
+	EventPriceDrop EventKind = iota
+
+	// EventBelowThreshold means the price went below a set amount.
+	EventBelowThreshold
+
+	// EventAllTimeLow means the price is lower than in any earlier snapshot.
+	EventAllTimeLow
+
+	// EventBackInStock means the item can be bought again after being
+	// unavailable.
+	EventBackInStock
+)
+
+// Event is a rule firing for an item, to be sent to a Notifier.
+type Event struct {
+	// Kind is the reason the rule fired.
+	Kind EventKind
+
+	// WishlistID is the ID of the wishlist the item is on.
+	WishlistID string
+
+	// Item is the item as it is in the latest snapshot.
+	Item *amazon.Item
+
+	// Current is the item's latest price and availability.
+	Current *Point
+
+	// Previous is the point the latest one was compared with, if any.
+	Previous *Point
+
+	// Message describes the event, e.g.,
+	// "Cat Litter dropped 20.0% to $12.00 (was $15.00)".
+	Message string
+}
+
+// Rule decides whether the latest point in a history calls for an event.
+// It returns nil if not. The Tracker fills in the event's WishlistID and
+// Item.
+type Rule func(history *History) *Event
+
+// String returns a short description of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventPriceDrop:
+		return "price drop"
+	case EventBelowThreshold:
+		return "below threshold"
+	case EventAllTimeLow:
+		return "all-time low"
+	case EventBackInStock:
+		return "back in stock"
+	}
+	return "unknown"
+}
+
+// String returns the event's message.
+func (e *Event) String() string {
+	return e.Message
+}
+
+// DropBy returns a rule that fires when the price is at least the given
+// percentage lower than the previous known price, e.g., 10 for 10%.
+func DropBy(percent float64) Rule {
+	return func(history *History) *Event {
+		current := history.Latest()
+		if current == nil || current.Price == nil {
+			return nil
+		}
+		previous := history.previousPriced(current.Price.Currency)
+		if previous == nil || previous.Price.Amount <= 0 {
+			return nil
+		}
+
+		drop := (previous.Price.Amount - current.Price.Amount) / previous.Price.Amount * 100
+		if drop < percent || drop <= 0 {
+			return nil
+		}
+		return &Event{
+			Kind:     EventPriceDrop,
+			Current:  current,
+			Previous: previous,
+			Message: fmt.Sprintf("%s dropped %.1f%% to %s (was %s)", history.Name, drop,
+				priceText(current), priceText(previous)),
+		}
+	}
+}
+
+// Below returns a rule that fires when the price goes below the given amount
+// in the given currency, e.g., 20 and "USD". It fires once when the price
+// crosses the threshold, not again while it stays below.
+func Below(amount float64, currency string) Rule {
+	return func(history *History) *Event {
+		current := history.Latest()
+		if current == nil || current.Price == nil || current.Price.Currency != currency ||
+			current.Price.Amount >= amount {
+			return nil
+		}
+		previous := history.previousPriced(currency)
+		if previous != nil && previous.Price.Amount < amount {
+			return nil
+		}
+		return &Event{
+			Kind:     EventBelowThreshold,
+			Current:  current,
+			Previous: previous,
+			Message: fmt.Sprintf("%s is %s, below %.2f %s", history.Name,
+				priceText(current), amount, currency),
+		}
+	}
+}
+
+// AllTimeLow returns a rule that fires when the price is lower than in every
+// earlier point of the history.
+func AllTimeLow() Rule {
+	return func(history *History) *Event {
+		current := history.Latest()
+		if current == nil || current.Price == nil {
+			return nil
+		}
+
+		earlier := &History{Points: history.Points[:len(history.Points)-1]}
+		lowest := earlier.Lowest(current.Price.Currency)
+		if lowest == nil || current.Price.Amount >= lowest.Price.Amount {
+			return nil
+		}
+		return &Event{
+			Kind:     EventAllTimeLow,
+			Current:  current,
+			Previous: lowest,
+			Message: fmt.Sprintf("%s is at an all-time low of %s (was %s)", history.Name,
+				priceText(current), priceText(lowest)),
+		}
+	}
+}
+
+// BackInStock returns a rule that fires when the item can be bought again
+// after being unavailable in the previous point.
+func BackInStock() Rule {
+	return func(history *History) *Event {
+		if len(history.Points) < 2 {
+			return nil
+		}
+		current := history.Latest()
+		previous := history.Points[len(history.Points)-2]
+		if previous.Availability != amazon.AvailabilityUnavailable ||
+			!current.Availability.IsAvailable() {
+			return nil
+		}
+
+		message := fmt.Sprintf("%s is back in stock", history.Name)
+		if current.RawPrice != "" {
+			message += " at " + priceText(current)
+		}
+		return &Event{
+			Kind:     EventBackInStock,
+			Current:  current,
+			Previous: previous,
+			Message:  message,
+		}
+	}
+}
+
+// priceText returns the price as Amazon wrote it, or as parsed if Amazon's
+// text wasn't kept.
+func priceText(point *Point) string {
+	if point.RawPrice != "" {
+		return point.RawPrice
+	}
+	if point.Price != nil {
+		return point.Price.String()
+	}
+	return "no price"
+}
@@ -0,0 +1,71 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/cheshire137/gogoamazonwish/internal/amazontest"
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHistory(prices ...float64) *History {
+	history := &History{WishlistID: amazontest.WishlistID, ItemID: amazontest.ItemID,
+		ASIN: amazontest.ASIN, Name: "Cat Litter"}
+	for i, amount := range prices {
+		point := &Point{Time: amazontest.Start.AddDate(0, 0, i), Availability: amazon.AvailabilityInStock}
+		if amount > 0 {
+			point.Price = &amazon.Price{Amount: amount, Currency: "USD"}
+		}
+		history.Points = append(history.Points, point)
+	}
+	return history
+}
+
+func TestDropBy(t *testing.T) {
+	rule := DropBy(10)
+	require.Nil(t, rule(newTestHistory(10)))
+	require.Nil(t, rule(newTestHistory(10, 9.5)))
+	require.Nil(t, rule(newTestHistory(10, 12)))
+
+	// Points without a price are skipped when finding the previous price.
+	event := rule(newTestHistory(10, 0, 8))
+	require.NotNil(t, event)
+	require.Equal(t, "Cat Litter dropped 20.0% to 8.00 USD (was 10.00 USD)", event.Message)
+}
+
+func TestBelow(t *testing.T) {
+	rule := Below(20, "USD")
+	require.NotNil(t, rule(newTestHistory(19)))
+	require.NotNil(t, rule(newTestHistory(25, 19)))
+	require.Nil(t, rule(newTestHistory(19, 18)))
+	require.Nil(t, rule(newTestHistory(25, 20)))
+	require.Nil(t, Below(20, "EUR")(newTestHistory(25, 19)))
+}
+
+func TestAllTimeLow(t *testing.T) {
+	rule := AllTimeLow()
+	require.Nil(t, rule(newTestHistory(10)))
+	require.Nil(t, rule(newTestHistory(8, 12, 9)))
+	require.NotNil(t, rule(newTestHistory(8, 12, 7.99)))
+}
+
+func TestBackInStock(t *testing.T) {
+	rule := BackInStock()
+	history := newTestHistory(10, 0, 10)
+	require.Nil(t, rule(history))
+
+	history.Points[1].Availability = amazon.AvailabilityUnavailable
+	event := rule(history)
+	require.NotNil(t, event)
+	require.Equal(t, EventBackInStock, event.Kind)
+	require.Equal(t, "Cat Litter is back in stock", event.Message)
+}
+
+func TestHistoryAdd(t *testing.T) {
+	history := newTestHistory(10, 11)
+	require.False(t, history.add(&Point{Time: amazontest.Start.AddDate(0, 0, -1)}))
+	require.False(t, history.add(&Point{Time: amazontest.Start}))
+	require.True(t, history.add(&Point{Time: amazontest.Start.AddDate(0, 0, 5)}))
+	require.Len(t, history.Points, 4)
+	require.True(t, history.Points[0].Time.Before(history.Points[1].Time))
+}
@@ -0,0 +1,181 @@
+package prices
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+	"github.com/cheshire137/gogoamazonwish/pkg/store"
+)
+
+// Notifier is told about each event a rule fires, e.g., to send an email or
+// post to a chat room.
+type Notifier interface {
+	Notify(event *Event) error
+}
+
+// NotifierFunc lets an ordinary function be used as a Notifier.
+type NotifierFunc func(event *Event) error
+
+// Notify calls f(event).
+func (f NotifierFunc) Notify(event *Event) error {
+	return f(event)
+}
+
+// WriterNotifier returns a Notifier that writes each event's message on its
+// own line, e.g., to os.Stdout or a log file.
+func WriterNotifier(w io.Writer) Notifier {
+	var mutex sync.Mutex
+	return NotifierFunc(func(event *Event) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		_, err := fmt.Fprintln(w, event.Message)
+		return err
+	})
+}
+
+// Tracker keeps the price history of the items in the snapshots it is given
+// and evaluates its rules each time an item gets a new latest price. It is
+// safe for concurrent use.
+type Tracker struct {
+	rules     []Rule
+	notifier  Notifier
+	mutex     sync.Mutex
+	histories map[historyID]*History
+}
+
+// NewTracker returns a tracker that sends the events its rules fire to the
+// notifier. The notifier may be nil if you only want the events Track
+// returns.
+func NewTracker(notifier Notifier, rules ...Rule) *Tracker {
+	return &Tracker{
+		rules:     rules,
+		notifier:  notifier,
+		histories: map[historyID]*History{},
+	}
+}
+
+// Track adds the price of each item in the snapshot to its history and
+// evaluates the rules for the items whose price is now the latest. Events are
+// sent to the notifier and returned. If the notifier fails, every event is
+// still attempted and the first error is returned.
+func (t *Tracker) Track(snapshot *amazon.Snapshot) ([]*Event, error) {
+	if snapshot == nil {
+		return nil, errors.New("Cannot track a missing snapshot")
+	}
+
+	t.mutex.Lock()
+	events := []*Event{}
+	for _, item := range snapshot.Items {
+		history, latest := t.record(item, snapshot)
+		if !latest {
+			// Older snapshots fill in history but don't cause events, and
+			// neither does tracking the same snapshot twice.
+			continue
+		}
+		for _, rule := range t.rules {
+			if event := rule(history); event != nil {
+				event.WishlistID = snapshot.ID
+				event.Item = item
+				events = append(events, event)
+			}
+		}
+	}
+	t.mutex.Unlock()
+
+	if t.notifier == nil {
+		return events, nil
+	}
+
+	var firstErr error
+	for _, event := range events {
+		if err := t.notifier.Notify(event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return events, firstErr
+}
+
+// Load adds the prices in every stored snapshot of the wishlist to the
+// histories, without evaluating rules, so a new tracker picks up where the
+// last run left off.
+func (t *Tracker) Load(s store.Store, wishlistID string) error {
+	times, err := s.Snapshots(wishlistID)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, takenAt := range times {
+		snapshot, err := s.At(wishlistID, takenAt)
+		if err != nil {
+			return err
+		}
+		for _, item := range snapshot.Items {
+			t.record(item, snapshot)
+		}
+	}
+	return nil
+}
+
+// History returns a copy of the price history of the item with the given ID
+// on the given wishlist, or nil if the tracker has not seen it there. Changing
+// the copy doesn't change the tracker's history.
+func (t *Tracker) History(wishlistID string, itemID string) *History {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	history, ok := t.histories[historyID{wishlistID: wishlistID, itemID: itemID}]
+	if !ok {
+		return nil
+	}
+	return history.copy()
+}
+
+// HistoriesByASIN returns copies of the price histories of every item whose
+// latest snapshot had the given product ID, across all wishlists, sorted by
+// wishlist ID and then item ID. Returns an empty slice if there are none.
+func (t *Tracker) HistoriesByASIN(asin string) []*History {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	histories := []*History{}
+	if asin == "" {
+		return histories
+	}
+	for _, history := range t.histories {
+		if history.ASIN == asin {
+			histories = append(histories, history.copy())
+		}
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		if histories[i].WishlistID != histories[j].WishlistID {
+			return histories[i].WishlistID < histories[j].WishlistID
+		}
+		return histories[i].ItemID < histories[j].ItemID
+	})
+	return histories
+}
+
+// record adds the item's price in the snapshot to its history. Returns the
+// history and whether the snapshot's point is newly the latest in it.
+func (t *Tracker) record(item *amazon.Item, snapshot *amazon.Snapshot) (*History, bool) {
+	id := historyID{wishlistID: snapshot.ID, itemID: item.ID}
+	history, ok := t.histories[id]
+	if !ok {
+		history = &History{WishlistID: id.wishlistID, ItemID: id.itemID}
+		t.histories[id] = history
+	}
+
+	latest := history.add(newPoint(item, snapshot.TakenAt))
+	if latest {
+		history.Name = item.Name
+		history.ASIN = item.ASIN
+	}
+	return history, latest
+}
@@ -0,0 +1,171 @@
+package prices
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/cheshire137/gogoamazonwish/internal/amazontest"
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+	"github.com/cheshire137/gogoamazonwish/pkg/store"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshot(day int, price string, availability amazon.Availability) *amazon.Snapshot {
+	item := amazontest.NewItem(amazontest.ItemID, price)
+	item.Availability = availability
+	return amazontest.NewSnapshot(day, item)
+}
+
+func TestTracker(t *testing.T) {
+	var buf bytes.Buffer
+	tracker := NewTracker(WriterNotifier(&buf), DropBy(10), Below(10, "USD"), BackInStock())
+
+	events, err := tracker.Track(newTestSnapshot(0, "$15.00", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	events, err = tracker.Track(newTestSnapshot(1, "", amazon.AvailabilityUnavailable))
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	events, err = tracker.Track(newTestSnapshot(2, "$9.50", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, EventPriceDrop, events[0].Kind)
+	require.Equal(t, amazontest.WishlistID, events[0].WishlistID)
+	require.Equal(t, amazontest.ItemID, events[0].Item.ID)
+	require.Equal(t, EventBelowThreshold, events[1].Kind)
+	require.Equal(t, EventBackInStock, events[2].Kind)
+	require.Equal(t, `Purina Tidy Cats Non-Clumping Cat Litter dropped 36.7% to $9.50 (was $15.00)
+Purina Tidy Cats Non-Clumping Cat Litter is $9.50, below 10.00 USD
+Purina Tidy Cats Non-Clumping Cat Litter is back in stock at $9.50
+`, buf.String())
+
+	// Tracking the same snapshot again, or an older one, doesn't repeat
+	// events.
+	events, err = tracker.Track(newTestSnapshot(2, "$9.50", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	require.Empty(t, events)
+	events, err = tracker.Track(newTestSnapshot(-1, "$20.00", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	history := tracker.History(amazontest.WishlistID, amazontest.ItemID)
+	require.NotNil(t, history)
+	require.Equal(t, amazontest.ItemName, history.Name)
+	require.Equal(t, amazontest.ASIN, history.ASIN)
+	require.Len(t, history.Points, 4)
+	require.Equal(t, "$20.00", history.Points[0].RawPrice)
+	require.Equal(t, 9.5, history.Latest().Price.Amount)
+	require.Equal(t, history.Latest(), history.Lowest("USD"))
+	require.Nil(t, history.Lowest("EUR"))
+	require.Nil(t, tracker.History(amazontest.WishlistID, "nope"))
+
+	history.Points[0].Price.Amount = 1
+	require.Equal(t, 20.0, tracker.History(amazontest.WishlistID, amazontest.ItemID).Points[0].Price.Amount,
+		"should not share points with the tracker")
+}
+
+func TestTrackerKeepsItemsOfTheSameProductApart(t *testing.T) {
+	tracker := NewTracker(nil, DropBy(10))
+
+	small := amazontest.NewItem(amazontest.ItemID, "$15.00")
+	large := amazontest.NewItem("I3H7VKP1GZXW9K", "$30.00")
+	_, err := tracker.Track(amazontest.NewSnapshot(0, small, large))
+	require.NoError(t, err)
+
+	events, err := tracker.Track(amazontest.NewSnapshot(1,
+		amazontest.NewItem(amazontest.ItemID, "$15.00"), amazontest.NewItem("I3H7VKP1GZXW9K", "$30.00")))
+	require.NoError(t, err)
+	require.Empty(t, events, "should not compare one item's price with another's")
+
+	history := tracker.History(amazontest.WishlistID, "I3H7VKP1GZXW9K")
+	require.Equal(t, amazontest.ASIN, history.ASIN)
+	require.Len(t, history.Points, 2)
+	require.Equal(t, 30.0, history.Latest().Price.Amount)
+	require.Len(t, tracker.History(amazontest.WishlistID, amazontest.ItemID).Points, 2)
+}
+
+func TestTrackerKeepsWishlistsApart(t *testing.T) {
+	tracker := NewTracker(nil, DropBy(10), AllTimeLow())
+
+	_, err := tracker.Track(newTestSnapshot(0, "$15.00", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+
+	// The same product on another list, and cheaper there, starts a history
+	// of its own rather than firing a drop against the first list's price.
+	other := newTestSnapshot(1, "$9.00", amazon.AvailabilityInStock)
+	other.ID = "2MLKJ1W2B9X4Q"
+	events, err := tracker.Track(other)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// Back on the first list, the other list's lower price doesn't count.
+	events, err = tracker.Track(newTestSnapshot(2, "$14.00", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "Purina Tidy Cats Non-Clumping Cat Litter is at an all-time low of $14.00 (was $15.00)", events[0].Message)
+
+	require.Len(t, tracker.History(amazontest.WishlistID, amazontest.ItemID).Points, 2)
+	history := tracker.History("2MLKJ1W2B9X4Q", amazontest.ItemID)
+	require.Equal(t, "2MLKJ1W2B9X4Q", history.WishlistID)
+	require.Len(t, history.Points, 1)
+	require.Nil(t, tracker.History("2MLKJ1W2B9X4Q", "nope"))
+
+	histories := tracker.HistoriesByASIN(amazontest.ASIN)
+	require.Len(t, histories, 2, "should find the product on both lists")
+	require.Equal(t, "2MLKJ1W2B9X4Q", histories[0].WishlistID)
+	require.Equal(t, amazontest.WishlistID, histories[1].WishlistID)
+	histories[1].Points[0].RawPrice = "$1.00"
+	require.Equal(t, "$15.00", tracker.History(amazontest.WishlistID, amazontest.ItemID).Points[0].RawPrice)
+	require.Empty(t, tracker.HistoriesByASIN("B000000000"))
+	require.Empty(t, tracker.HistoriesByASIN(""))
+}
+
+func TestTrackMissingSnapshot(t *testing.T) {
+	_, err := NewTracker(nil, DropBy(10)).Track(nil)
+	require.Error(t, err)
+}
+
+func TestTrackerNotifierError(t *testing.T) {
+	calls := 0
+	notifier := NotifierFunc(func(event *Event) error {
+		calls++
+		return errors.New("Could not send email")
+	})
+	tracker := NewTracker(notifier, DropBy(10), AllTimeLow())
+
+	_, err := tracker.Track(newTestSnapshot(0, "$15.00", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	events, err := tracker.Track(newTestSnapshot(1, "$12.00", amazon.AvailabilityInStock))
+	require.Error(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, 2, calls)
+}
+
+func TestTrackerLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prices")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewFileStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, s.Save(newTestSnapshot(0, "$15.00", amazon.AvailabilityInStock)))
+	require.NoError(t, s.Save(newTestSnapshot(1, "$11.00", amazon.AvailabilityInStock)))
+
+	tracker := NewTracker(nil, AllTimeLow())
+	require.NoError(t, tracker.Load(s, amazontest.WishlistID))
+	require.Len(t, tracker.History(amazontest.WishlistID, amazontest.ItemID).Points, 2)
+
+	events, err := tracker.Track(newTestSnapshot(2, "$12.00", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	events, err = tracker.Track(newTestSnapshot(3, "$10.00", amazon.AvailabilityInStock))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "Purina Tidy Cats Non-Clumping Cat Litter is at an all-time low of $10.00 (was $11.00)", events[0].Message)
+}
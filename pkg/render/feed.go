@@ -0,0 +1,411 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+// FeedFormat is a kind of syndication feed a wishlist can be served as.
+type FeedFormat int
+
+const (
+	// FeedAtom is an Atom 1.0 feed.
+	FeedAtom FeedFormat = iota
+
+	// FeedRSS is an RSS 2.0 feed.
+	FeedRSS
+)
+
+const (
+	atomNamespace     = "http://www.w3.org/2005/Atom"
+	rssVersion        = "2.0"
+	defaultFeedAuthor = "Amazon"
+
+	atomContentType = "application/atom+xml; charset=utf-8"
+	rssContentType  = "application/rss+xml; charset=utf-8"
+)
+
+var imageContentTypes = map[string]string{
+	".gif":  "image/gif",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Links   []atomLink `xml:"link"`
+	Summary string     `xml:"summary,omitempty"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// String returns the name of the feed format.
+func (f FeedFormat) String() string {
+	switch f {
+	case FeedAtom:
+		return "atom"
+	case FeedRSS:
+		return "rss"
+	}
+	return "unknown"
+}
+
+// Atom writes the snapshot as an Atom feed with an entry for each item. Entry
+// IDs are based on the item ID, so feed readers show each item once. Items
+// Amazon gives no date for are dated when the snapshot was taken; see
+// StoreFeedHandler for dates that stay the same between snapshots.
+func Atom(w io.Writer, snapshot *amazon.Snapshot) error {
+	return writeAtom(w, snapshot, nil)
+}
+
+// RSS writes the snapshot as an RSS 2.0 feed with an item for each wishlist
+// item. Items are dated like in Atom.
+func RSS(w io.Writer, snapshot *amazon.Snapshot) error {
+	return writeRSS(w, snapshot, nil)
+}
+
+// writeAtom writes an Atom feed, dating items Amazon gives no date for by
+// when they were first seen, if known, keyed by item ID.
+func writeAtom(w io.Writer, snapshot *amazon.Snapshot, firstSeen map[string]time.Time) error {
+	feed := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      feedID(snapshot),
+		Title:   title(snapshot),
+		Updated: feedUpdated(snapshot).Format(time.RFC3339),
+		Author:  atomPerson{Name: defaultFeedAuthor},
+		Entries: make([]atomEntry, 0, len(snapshot.Items)),
+	}
+	if snapshot.URL != "" {
+		feed.Links = append(feed.Links, atomLink{Rel: "alternate", Href: snapshot.URL})
+	}
+
+	for _, item := range snapshot.Items {
+		entry := atomEntry{
+			ID:      itemGUID(snapshot, item),
+			Title:   item.Name,
+			Updated: itemUpdated(snapshot, item, firstSeen).Format(time.RFC3339),
+			Summary: itemSummary(item),
+		}
+		if url := item.CanonicalURL(); url != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "alternate", Href: url})
+		}
+		if item.ImageURL != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure",
+				Type: imageContentType(item.ImageURL), Href: item.ImageURL})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return writeXML(w, feed)
+}
+
+// writeRSS writes an RSS feed, dating items like writeAtom.
+func writeRSS(w io.Writer, snapshot *amazon.Snapshot, firstSeen map[string]time.Time) error {
+	feed := rssFeed{
+		Version: rssVersion,
+		Channel: rssChannel{
+			Title:         title(snapshot),
+			Link:          snapshot.URL,
+			Description:   fmt.Sprintf("Items on %s", title(snapshot)),
+			LastBuildDate: feedUpdated(snapshot).Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(snapshot.Items)),
+		},
+	}
+
+	for _, item := range snapshot.Items {
+		rss := rssItem{
+			Title:       item.Name,
+			Link:        item.CanonicalURL(),
+			Description: itemSummary(item),
+			GUID:        rssGUID{Value: itemGUID(snapshot, item)},
+			PubDate:     itemUpdated(snapshot, item, firstSeen).Format(time.RFC1123Z),
+		}
+		if item.ImageURL != "" {
+			// Amazon doesn't say how big images are, and readers accept 0.
+			rss.Enclosure = &rssEnclosure{URL: item.ImageURL,
+				Type: imageContentType(item.ImageURL)}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rss)
+	}
+
+	return writeXML(w, feed)
+}
+
+// FeedHandler returns an HTTP handler that serves the snapshot returned by
+// load as a feed in the given format. load is called for every request, one
+// request at a time. Feed readers poll often, so load should not contact
+// Amazon, which would risk a robot check on every poll; have it return a
+// stored snapshot instead, or use StoreFeedHandler.
+func FeedHandler(format FeedFormat, load func() (*amazon.Snapshot, error)) http.Handler {
+	return feedHandler(format, func() (*amazon.Snapshot, map[string]time.Time, error) {
+		snapshot, err := load()
+		return snapshot, nil, err
+	})
+}
+
+// SnapshotSource is where StoreFeedHandler reads snapshots from. Any
+// store.Store is one.
+type SnapshotSource interface {
+	// Snapshots returns when each snapshot of the wishlist was taken, oldest
+	// first.
+	Snapshots(wishlistID string) ([]time.Time, error)
+
+	// At returns the snapshot of the wishlist taken at the given time.
+	At(wishlistID string, t time.Time) (*amazon.Snapshot, error)
+}
+
+// StoreFeedHandler returns an HTTP handler that serves the latest snapshot of
+// the wishlist in the store as a feed in the given format. Amazon is never
+// contacted, so save new snapshots to the store separately, e.g., on a
+// schedule. Items Amazon gives no date for are dated by the first snapshot in
+// the store they appear in, so their dates don't change between snapshots.
+// The handler remembers those dates, so after the first request it only reads
+// the latest snapshot and any saved since the last request. Snapshots saved
+// with an earlier time than one already read are not looked at.
+func StoreFeedHandler(format FeedFormat, s SnapshotSource, wishlistID string) http.Handler {
+	seen := &firstSeenCache{times: map[string]time.Time{}}
+
+	return feedHandler(format, func() (*amazon.Snapshot, map[string]time.Time, error) {
+		times, err := s.Snapshots(wishlistID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(times) < 1 {
+			return nil, nil, fmt.Errorf("No snapshots of wishlist %s", wishlistID)
+		}
+
+		snapshot, err := s.At(wishlistID, times[len(times)-1])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, t := range times[:len(times)-1] {
+			if !t.After(seen.readThrough) {
+				continue
+			}
+			older, err := s.At(wishlistID, t)
+			if err != nil {
+				return nil, nil, err
+			}
+			seen.add(older)
+		}
+		seen.add(snapshot)
+
+		return snapshot, seen.undatedItems(snapshot), nil
+	})
+}
+
+// firstSeenCache remembers when each item on a wishlist first appeared in the
+// snapshots read so far, keyed by item ID.
+type firstSeenCache struct {
+	times map[string]time.Time
+
+	// readThrough is when the latest snapshot read was taken.
+	readThrough time.Time
+}
+
+// add records the items in the snapshot not seen before, which must be taken
+// after every snapshot already added.
+func (c *firstSeenCache) add(snapshot *amazon.Snapshot) {
+	for _, item := range snapshot.Items {
+		if _, ok := c.times[item.ID]; !ok {
+			c.times[item.ID] = snapshot.TakenAt
+		}
+	}
+	if snapshot.TakenAt.After(c.readThrough) {
+		c.readThrough = snapshot.TakenAt
+	}
+}
+
+// undatedItems returns when each of the snapshot's undated items was first
+// seen. It returns a new map, so the feed can be written while another
+// request updates the cache.
+func (c *firstSeenCache) undatedItems(snapshot *amazon.Snapshot) map[string]time.Time {
+	firstSeen := map[string]time.Time{}
+	for _, item := range snapshot.Items {
+		if dateAdded(item) == nil {
+			firstSeen[item.ID] = c.times[item.ID]
+		}
+	}
+	return firstSeen
+}
+
+func feedHandler(format FeedFormat, load func() (*amazon.Snapshot, map[string]time.Time, error)) http.Handler {
+	var mutex sync.Mutex
+	write := writeAtom
+	contentType := atomContentType
+	if format == FeedRSS {
+		write = writeRSS
+		contentType = rssContentType
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		snapshot, firstSeen, err := load()
+		mutex.Unlock()
+		if err != nil {
+			// Errors can include file paths and proxy URLs, so they're
+			// logged rather than shown to whoever requested the feed.
+			log.Printf("Could not load wishlist for feed: %s", err)
+			http.Error(w, "Could not load wishlist", http.StatusBadGateway)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := write(&buf, snapshot, firstSeen); err != nil {
+			log.Printf("Could not write feed: %s", err)
+			http.Error(w, "Could not write feed", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Write(buf.Bytes())
+	})
+}
+
+func writeXML(w io.Writer, feed interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// feedID returns a stable ID for the wishlist's feed.
+func feedID(snapshot *amazon.Snapshot) string {
+	if snapshot.URL != "" {
+		return snapshot.URL
+	}
+	return "urn:amazon:wishlist:" + snapshot.ID
+}
+
+// itemGUID returns a stable ID for an item on the wishlist, which stays the
+// same across snapshots.
+func itemGUID(snapshot *amazon.Snapshot, item *amazon.Item) string {
+	return fmt.Sprintf("urn:amazon:wishlist:%s:item:%s", snapshot.ID, item.ID)
+}
+
+// feedUpdated returns when the newest item was added, or when the snapshot
+// was taken if no item has a date.
+func feedUpdated(snapshot *amazon.Snapshot) time.Time {
+	var updated *time.Time
+	for _, item := range snapshot.Items {
+		if date := dateAdded(item); date != nil && (updated == nil || date.After(*updated)) {
+			updated = date
+		}
+	}
+	if updated == nil {
+		return snapshot.TakenAt
+	}
+	return *updated
+}
+
+// itemUpdated returns when the item was added, or else when it was first
+// seen, or else when the snapshot was taken.
+func itemUpdated(snapshot *amazon.Snapshot, item *amazon.Item, firstSeen map[string]time.Time) time.Time {
+	if date := dateAdded(item); date != nil {
+		return *date
+	}
+	if seen, ok := firstSeen[item.ID]; ok {
+		return seen
+	}
+	return snapshot.TakenAt
+}
+
+// itemSummary describes the item's price, quantities, and note in one line,
+// e.g., "$15.96 - 2 requested, 1 owned - For the kitchen".
+func itemSummary(item *amazon.Item) string {
+	parts := []string{}
+	if price := strings.TrimSpace(item.Price); price != "" {
+		parts = append(parts, price)
+	}
+	if q := quantity(item); q != "" {
+		parts = append(parts, q)
+	}
+	if comment := strings.TrimSpace(item.Comment); comment != "" {
+		parts = append(parts, comment)
+	}
+	return strings.Join(parts, " - ")
+}
+
+func imageContentType(url string) string {
+	if i := strings.IndexAny(url, "?#"); i > -1 {
+		url = url[:i]
+	}
+	if contentType, ok := imageContentTypes[strings.ToLower(path.Ext(url))]; ok {
+		return contentType
+	}
+	return "image/jpeg"
+}
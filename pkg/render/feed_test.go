@@ -0,0 +1,169 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+	"github.com/stretchr/testify/require"
+)
+
+func newFeedTestSnapshot() *amazon.Snapshot {
+	snapshot := newTestSnapshot()
+	snapshot.URL = "https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT"
+	snapshot.TakenAt = time.Date(2020, time.January, 4, 18, 30, 0, 0, time.UTC)
+	return snapshot
+}
+
+func TestAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Atom(&buf, newFeedTestSnapshot()))
+	require.Contains(t, buf.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+	require.Equal(t, "https://www.amazon.com/hz/wishlist/ls/3I6EQPZ8OB1DT", feed.ID)
+	require.Equal(t, "NHA Wish List", feed.Title)
+	require.Equal(t, "Amazon", feed.Author.Name)
+	require.Equal(t, "2019-07-10T00:00:00-07:00", feed.Updated)
+	require.Len(t, feed.Entries, 2)
+
+	entry := feed.Entries[0]
+	require.Equal(t, "urn:amazon:wishlist:3I6EQPZ8OB1DT:item:I2G6UJO0FYWV8J", entry.ID)
+	require.Equal(t, "Purina Tidy Cats *Non-Clumping* Cat Litter", entry.Title)
+	require.Equal(t, "2019-07-10T00:00:00-07:00", entry.Updated)
+	require.Equal(t, "$15.96 - 50 requested, 11 owned - For the <shelter> cats", entry.Summary)
+	require.Equal(t, []atomLink{
+		{Rel: "alternate", Href: "https://www.amazon.com/dp/B0018CLTKE"},
+		{Rel: "enclosure", Type: "image/jpeg", Href: "https://images-na.ssl-images-amazon.com/images/I/81xVpUqL3uL._SS135_.jpg"},
+	}, entry.Links)
+
+	undated := feed.Entries[1]
+	require.Equal(t, "2020-01-04T18:30:00Z", undated.Updated)
+	require.Empty(t, undated.Links)
+}
+
+func TestRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RSS(&buf, newFeedTestSnapshot()))
+
+	var feed rssFeed
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+	require.Equal(t, "2.0", feed.Version)
+	require.Equal(t, "Items on NHA Wish List", feed.Channel.Description)
+	require.Len(t, feed.Channel.Items, 2)
+
+	item := feed.Channel.Items[0]
+	require.Equal(t, "urn:amazon:wishlist:3I6EQPZ8OB1DT:item:I2G6UJO0FYWV8J", item.GUID.Value)
+	require.False(t, item.GUID.IsPermaLink)
+	require.Equal(t, "Wed, 10 Jul 2019 00:00:00 -0700", item.PubDate)
+	require.Equal(t, "https://www.amazon.com/dp/B0018CLTKE", item.Link)
+	require.Equal(t, &rssEnclosure{
+		URL:  "https://images-na.ssl-images-amazon.com/images/I/81xVpUqL3uL._SS135_.jpg",
+		Type: "image/jpeg",
+	}, item.Enclosure)
+	require.Nil(t, feed.Channel.Items[1].Enclosure)
+}
+
+func TestFeedHandler(t *testing.T) {
+	load := func() (*amazon.Snapshot, error) {
+		return newFeedTestSnapshot(), nil
+	}
+
+	recorder := httptest.NewRecorder()
+	FeedHandler(FeedRSS, load).ServeHTTP(recorder, httptest.NewRequest("GET", "/feed.rss", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/rss+xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), `<rss version="2.0">`)
+
+	recorder = httptest.NewRecorder()
+	FeedHandler(FeedAtom, load).ServeHTTP(recorder, httptest.NewRequest("GET", "/feed.atom", nil))
+	require.Equal(t, "application/atom+xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	failing := func() (*amazon.Snapshot, error) {
+		return nil, errors.New("Amazon thinks you're a robot")
+	}
+	recorder = httptest.NewRecorder()
+	FeedHandler(FeedAtom, failing).ServeHTTP(recorder, httptest.NewRequest("GET", "/feed.atom", nil))
+	require.Equal(t, http.StatusBadGateway, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), "robot", "should not show the error")
+}
+
+// testSnapshotSource holds snapshots of one wishlist, oldest first, and counts
+// how many are read.
+type testSnapshotSource struct {
+	snapshots []*amazon.Snapshot
+	reads     int
+}
+
+func (s *testSnapshotSource) Snapshots(wishlistID string) ([]time.Time, error) {
+	times := []time.Time{}
+	for _, snapshot := range s.snapshots {
+		times = append(times, snapshot.TakenAt)
+	}
+	return times, nil
+}
+
+func (s *testSnapshotSource) At(wishlistID string, t time.Time) (*amazon.Snapshot, error) {
+	s.reads++
+	for _, snapshot := range s.snapshots {
+		if snapshot.TakenAt.Equal(t) {
+			return snapshot, nil
+		}
+	}
+	return nil, errors.New("No snapshot found")
+}
+
+func TestStoreFeedHandler(t *testing.T) {
+	s := &testSnapshotSource{}
+
+	recorder := httptest.NewRecorder()
+	StoreFeedHandler(FeedAtom, s, "3I6EQPZ8OB1DT").ServeHTTP(recorder,
+		httptest.NewRequest("GET", "/feed.atom", nil))
+	require.Equal(t, http.StatusBadGateway, recorder.Code, "should fail with no snapshots")
+
+	first := newFeedTestSnapshot()
+	first.Items = first.Items[1:]
+	latest := newFeedTestSnapshot()
+	latest.TakenAt = first.TakenAt.Add(24 * time.Hour)
+	s.snapshots = []*amazon.Snapshot{first, latest}
+
+	handler := StoreFeedHandler(FeedAtom, s, "3I6EQPZ8OB1DT")
+	get := func() atomFeed {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/feed.atom", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var feed atomFeed
+		require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &feed))
+		return feed
+	}
+
+	s.reads = 0
+	feed := get()
+	require.Equal(t, 2, s.reads, "should read each snapshot once")
+	require.Len(t, feed.Entries, 2)
+	require.Equal(t, "2019-07-10T00:00:00-07:00", feed.Entries[0].Updated)
+	require.Equal(t, "2020-01-04T18:30:00Z", feed.Entries[1].Updated,
+		"should date an undated item by when it was first seen")
+	s.reads = 0
+	require.Equal(t, feed, get(), "should be the same on every fetch")
+	require.Equal(t, 1, s.reads, "should only read the latest snapshot again")
+
+	newer := newFeedTestSnapshot()
+	newer.TakenAt = latest.TakenAt.Add(24 * time.Hour)
+	s.snapshots = append(s.snapshots, newer)
+	s.reads = 0
+	feed = get()
+	require.Equal(t, 1, s.reads, "should only read the new snapshot")
+	require.Equal(t, "2020-01-04T18:30:00Z", feed.Entries[1].Updated)
+}
+
+func TestImageContentType(t *testing.T) {
+	require.Equal(t, "image/png", imageContentType("https://example.com/a.PNG?x=1"))
+	require.Equal(t, "image/jpeg", imageContentType("https://example.com/a"))
+}
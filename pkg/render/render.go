@@ -0,0 +1,122 @@
+// Package render presents wishlist snapshots as Markdown or HTML gift guides,
+// using the built-in templates or your own text/template and html/template
+// files.
+package render
+
+import (
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+const defaultTitle = "Wishlist"
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+// Template is a parsed text/template or html/template. Templates are executed
+// with an *amazon.Snapshot.
+type Template interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// Funcs returns the functions available to templates, in addition to the
+// ones text/template provides:
+//
+//	title     the wishlist's name, or "Wishlist" if it has none
+//	url       the item's canonical product URL
+//	dateAdded when the item was added, as a *time.Time, or nil if unknown
+//	stars     the item's rating out of 5, or 0 if unknown
+//	quantity  how many the recipient wants and has, e.g., "2 requested, 1 owned"
+//	markdown  the text with Markdown formatting characters escaped
+//
+// Add them to your own templates with Funcs before parsing.
+func Funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"title":     title,
+		"url":       itemURL,
+		"dateAdded": dateAdded,
+		"stars":     stars,
+		"quantity":  quantity,
+		"markdown":  escapeMarkdown,
+	}
+}
+
+// Markdown writes the snapshot as a Markdown document, with a section for
+// each item.
+func Markdown(w io.Writer, snapshot *amazon.Snapshot) error {
+	return markdownTemplate.Execute(w, snapshot)
+}
+
+// HTML writes the snapshot as a standalone HTML page, with each item's image,
+// price, and quantities.
+func HTML(w io.Writer, snapshot *amazon.Snapshot) error {
+	return htmlTemplate.Execute(w, snapshot)
+}
+
+// ParseFiles parses your own template files, with Funcs available. The files
+// are parsed with html/template if the first one ends in ".html" or ".htm",
+// so values are escaped for HTML, and with text/template otherwise.
+func ParseFiles(filenames ...string) (Template, error) {
+	if len(filenames) < 1 {
+		return nil, errors.New("No template files given")
+	}
+
+	name := filepath.Base(filenames[0])
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == ".html" || ext == ".htm" {
+		return htmltemplate.New(name).Funcs(Funcs()).ParseFiles(filenames...)
+	}
+	return texttemplate.New(name).Funcs(Funcs()).ParseFiles(filenames...)
+}
+
+func title(snapshot *amazon.Snapshot) string {
+	if name := strings.TrimSpace(snapshot.Metadata.Name); name != "" {
+		return name
+	}
+	return defaultTitle
+}
+
+func itemURL(item *amazon.Item) string {
+	return item.CanonicalURL()
+}
+
+func dateAdded(item *amazon.Item) *time.Time {
+	date, err := item.DateAdded()
+	if err != nil {
+		return nil
+	}
+	return date
+}
+
+func stars(item *amazon.Item) float64 {
+	value, _ := item.Stars()
+	return value
+}
+
+func quantity(item *amazon.Item) string {
+	parts := []string{}
+	if item.RequestedCount > -1 {
+		parts = append(parts, strconv.Itoa(item.RequestedCount)+" requested")
+	}
+	if item.OwnedCount > -1 {
+		parts = append(parts, strconv.Itoa(item.OwnedCount)+" owned")
+	}
+	if item.PurchasedCount > -1 {
+		parts = append(parts, strconv.Itoa(item.PurchasedCount)+" purchased")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func escapeMarkdown(text string) string {
+	return markdownReplacer.Replace(strings.Join(strings.Fields(text), " "))
+}
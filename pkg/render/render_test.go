@@ -0,0 +1,98 @@
+package render
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshot() *amazon.Snapshot {
+	litter := amazon.NewItem("I2G6UJO0FYWV8J", "Purina Tidy Cats *Non-Clumping* Cat Litter",
+		"https://www.amazon.com/dp/B0018CLTKE/?coliid=I2G6UJO0FYWV8J")
+	litter.ImageURL = "https://images-na.ssl-images-amazon.com/images/I/81xVpUqL3uL._SS135_.jpg"
+	litter.Price = "$15.96"
+	litter.RequestedCount = 50
+	litter.OwnedCount = 11
+	litter.Priority = amazon.PriorityHigh
+	litter.RawDateAdded = "July 10, 2019"
+	litter.Comment = "For the <shelter> cats"
+
+	mystery := amazon.NewItem("I3TN2X0G8B7Q1K", "Mystery Box", "")
+
+	return &amazon.Snapshot{
+		ID:       "3I6EQPZ8OB1DT",
+		Metadata: amazon.Metadata{Name: "NHA Wish List"},
+		Items:    []*amazon.Item{litter, mystery},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Markdown(&buf, newTestSnapshot()))
+	require.Equal(t, `# NHA Wish List
+
+## [Purina Tidy Cats \*Non-Clumping\* Cat Litter](https://www.amazon.com/dp/B0018CLTKE)
+
+![](https://images-na.ssl-images-amazon.com/images/I/81xVpUqL3uL._SS135_.jpg)
+
+- **Price:** $15.96
+- **Quantity:** 50 requested, 11 owned
+- **Priority:** high
+- **Added:** July 10, 2019
+
+> For the \<shelter\> cats
+
+## Mystery Box
+
+- **Priority:** medium
+`, buf.String())
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, HTML(&buf, newTestSnapshot()))
+	html := buf.String()
+	require.Contains(t, html, "<title>NHA Wish List</title>")
+	require.Contains(t, html, `<a href="https://www.amazon.com/dp/B0018CLTKE">Purina Tidy Cats *Non-Clumping* Cat Litter</a>`)
+	require.Contains(t, html, `<img src="https://images-na.ssl-images-amazon.com/images/I/81xVpUqL3uL._SS135_.jpg" alt="">`)
+	require.Contains(t, html, `<p class="price">$15.96</p>`)
+	require.Contains(t, html, `<p class="details">50 requested, 11 owned</p>`)
+	require.Contains(t, html, "<blockquote>For the &lt;shelter&gt; cats</blockquote>")
+	require.Contains(t, html, "<h2>Mystery Box</h2>")
+}
+
+func TestParseFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	textPath := filepath.Join(dir, "guide.txt")
+	require.NoError(t, ioutil.WriteFile(textPath,
+		[]byte(`{{ title . }}:{{ range .Items }} {{ .Name }} <{{ url . }}>{{ with dateAdded . }} {{ .Format "2006-01-02" }}{{ end }};{{ end }}`), 0644))
+	htmlPath := filepath.Join(dir, "guide.html")
+	require.NoError(t, ioutil.WriteFile(htmlPath,
+		[]byte(`{{ range .Items }}<p>{{ .Comment }}</p>{{ end }}`), 0644))
+
+	tmpl, err := ParseFiles(textPath)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, newTestSnapshot()))
+	require.Equal(t, "NHA Wish List: Purina Tidy Cats *Non-Clumping* Cat Litter "+
+		"<https://www.amazon.com/dp/B0018CLTKE> 2019-07-10; Mystery Box <>;", buf.String())
+
+	tmpl, err = ParseFiles(htmlPath)
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, tmpl.Execute(&buf, newTestSnapshot()))
+	require.Equal(t, "<p>For the &lt;shelter&gt; cats</p><p></p>", buf.String())
+
+	_, err = ParseFiles()
+	require.Error(t, err)
+	_, err = ParseFiles(filepath.Join(dir, "missing.md"))
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+var (
+	markdownTemplate = texttemplate.Must(texttemplate.New("markdown").Funcs(Funcs()).Parse(
+		`# {{ markdown (title .) }}
+{{ range $item := .Items }}
+## {{ with url . }}[{{ markdown $item.Name }}]({{ . }}){{ else }}{{ markdown .Name }}{{ end }}
+{{ with .ImageURL }}
+![]({{ . }})
+{{ end }}{{ if .Byline }}
+by {{ markdown .Byline }}{{ with .Format }} ({{ markdown . }}){{ end }}
+{{ end }}
+{{ with .Price }}- **Price:** {{ markdown . }}
+{{ end }}{{ with quantity . }}- **Quantity:** {{ . }}
+{{ end }}- **Priority:** {{ .Priority }}
+{{ with .Rating }}- **Rating:** {{ markdown . }}
+{{ end }}{{ with .RawDateAdded }}- **Added:** {{ markdown . }}
+{{ end }}{{ with .Comment }}
+> {{ markdown . }}
+{{ end }}{{ end }}`))
+
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(Funcs()).Parse(
+		`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ title . }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 48em; margin: 2em auto; padding: 0 1em; color: #24292e; }
+.item { display: flex; gap: 1em; padding: 1em 0; border-top: 1px solid #e1e4e8; }
+.item img { width: 8em; height: 8em; object-fit: contain; flex-shrink: 0; }
+.item h2 { font-size: 1.2em; margin: 0 0 0.5em; }
+.price { font-weight: bold; }
+.details { color: #586069; margin: 0.25em 0; }
+blockquote { margin: 0.5em 0; padding-left: 1em; border-left: 3px solid #e1e4e8; }
+</style>
+</head>
+<body>
+<h1>{{ title . }}</h1>
+{{- range $item := .Items }}
+<div class="item">
+{{- with .ImageURL }}
+<img src="{{ . }}" alt="">
+{{- end }}
+<div>
+<h2>{{ with url . }}<a href="{{ . }}">{{ $item.Name }}</a>{{ else }}{{ .Name }}{{ end }}</h2>
+{{- if .Byline }}
+<p class="details">by {{ .Byline }}{{ with .Format }} ({{ . }}){{ end }}</p>
+{{- end }}
+{{- with .Price }}
+<p class="price">{{ . }}</p>
+{{- end }}
+{{- with quantity . }}
+<p class="details">{{ . }}</p>
+{{- end }}
+<p class="details">Priority: {{ .Priority }}</p>
+{{- with .Rating }}
+<p class="details">{{ . }}</p>
+{{- end }}
+{{- with .RawDateAdded }}
+<p class="details">Added {{ . }}</p>
+{{- end }}
+{{- with .Comment }}
+<blockquote>{{ . }}</blockquote>
+{{- end }}
+</div>
+</div>
+{{- end }}
+</body>
+</html>
+`))
+)
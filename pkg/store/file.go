@@ -0,0 +1,209 @@
+package store
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+const (
+	// snapshotFileLayout names snapshot files so they sort by time.
+	snapshotFileLayout = "20060102T150405.000000000Z"
+	snapshotFileExt    = ".json"
+)
+
+// FileStore keeps each snapshot as a JSON file, in a directory per wishlist.
+// It is safe for concurrent use within one process.
+type FileStore struct {
+	dir   string
+	mutex sync.Mutex
+	now   func() time.Time
+}
+
+// NewFileStore returns a store that keeps snapshots under the given
+// directory, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, now: time.Now}, nil
+}
+
+// Save writes the snapshot to a file named for when it was taken.
+func (s *FileStore) Save(snapshot *amazon.Snapshot) error {
+	if err := validateSnapshot(snapshot); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	dir := filepath.Join(s.dir, snapshot.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash can't leave half a snapshot.
+	file, err := ioutil.TempFile(dir, ".snapshot-")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), s.path(snapshot.ID, snapshot.TakenAt))
+}
+
+// Latest returns the most recent snapshot of the wishlist.
+func (s *FileStore) Latest(wishlistID string) (*amazon.Snapshot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	times, err := s.times(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) < 1 {
+		return nil, ErrNotFound
+	}
+	return s.read(wishlistID, times[len(times)-1])
+}
+
+// At returns the most recent snapshot of the wishlist taken at or before t.
+func (s *FileStore) At(wishlistID string, t time.Time) (*amazon.Snapshot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	times, err := s.times(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(times) - 1; i >= 0; i-- {
+		if !times[i].After(t) {
+			return s.read(wishlistID, times[i])
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Snapshots returns when each snapshot of the wishlist was taken.
+func (s *FileStore) Snapshots(wishlistID string) ([]time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.times(wishlistID)
+}
+
+// ItemHistory reads every snapshot of the wishlist and returns the versions
+// of the item in them.
+func (s *FileStore) ItemHistory(wishlistID, itemID string) ([]*ItemVersion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	times, err := s.times(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []*ItemVersion{}
+	for _, t := range times {
+		snapshot, err := s.read(wishlistID, t)
+		if err != nil {
+			return nil, err
+		}
+		if item := snapshot.Item(itemID); item != nil {
+			history = append(history, &ItemVersion{TakenAt: snapshot.TakenAt, Item: item})
+		}
+	}
+	return history, nil
+}
+
+// Prune deletes the files of the snapshots the policy does not keep.
+func (s *FileStore) Prune(wishlistID string, policy PrunePolicy) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	times, err := s.times(wishlistID)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, t := range policy.pruned(times, s.now()) {
+		if err := os.Remove(s.path(wishlistID, t)); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Close does nothing, since files are closed after every operation.
+func (s *FileStore) Close() error {
+	return nil
+}
+
+func (s *FileStore) path(wishlistID string, t time.Time) string {
+	name := t.UTC().Format(snapshotFileLayout) + snapshotFileExt
+	return filepath.Join(s.dir, wishlistID, name)
+}
+
+// times returns when the wishlist's snapshots were taken, oldest first, based
+// on their file names.
+func (s *FileStore) times(wishlistID string) ([]time.Time, error) {
+	if err := validateWishlistID(wishlistID); err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, wishlistID))
+	if os.IsNotExist(err) {
+		return []time.Time{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	times := []time.Time{}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, snapshotFileExt) {
+			continue
+		}
+		t, err := time.Parse(snapshotFileLayout, strings.TrimSuffix(name, snapshotFileExt))
+		if err != nil {
+			continue
+		}
+		times = append(times, t)
+	}
+	sortTimes(times)
+	return times, nil
+}
+
+func (s *FileStore) read(wishlistID string, t time.Time) (*amazon.Snapshot, error) {
+	data, err := ioutil.ReadFile(s.path(wishlistID, t))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot amazon.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,23 @@
+package store_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/cheshire137/gogoamazonwish/internal/storetest"
+	"github.com/cheshire137/gogoamazonwish/pkg/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewFileStore(dir)
+	require.NoError(t, err)
+	defer s.Close()
+
+	storetest.Run(t, s)
+}
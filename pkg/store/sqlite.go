@@ -0,0 +1,257 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS snapshots (
+		id INTEGER PRIMARY KEY,
+		wishlist_id TEXT NOT NULL,
+		taken_at INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		UNIQUE (wishlist_id, taken_at)
+	)`,
+	`CREATE TABLE IF NOT EXISTS snapshot_items (
+		snapshot_id INTEGER NOT NULL,
+		item_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (snapshot_id, item_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS snapshot_items_item_id ON snapshot_items (item_id)`,
+}
+
+// SQLiteStore keeps snapshots in a SQLite database, with a row per item so
+// item histories can be read without loading whole snapshots.
+//
+// The store works with any database/sql driver for SQLite. To stay free of
+// cgo, use the pure-Go modernc.org/sqlite driver:
+//
+//	import _ "modernc.org/sqlite"
+//
+//	db, err := sql.Open("sqlite", "wishlists.db")
+//	store, err := store.NewSQLiteStore(db)
+type SQLiteStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewSQLiteStore returns a store backed by the given SQLite database,
+// creating its tables if needed. Closing the store closes the database.
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	for _, statement := range sqliteSchema {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, err
+		}
+	}
+	return &SQLiteStore{db: db, now: time.Now}, nil
+}
+
+// Save inserts the snapshot and its items in one transaction.
+func (s *SQLiteStore) Save(snapshot *amazon.Snapshot) error {
+	if err := validateSnapshot(snapshot); err != nil {
+		return err
+	}
+
+	// Items get rows of their own, so leave them out of the snapshot's data.
+	withoutItems := *snapshot
+	withoutItems.Items = nil
+	data, err := json.Marshal(&withoutItems)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	takenAt := snapshot.TakenAt.UnixNano()
+	if err := deleteSnapshots(tx, `wishlist_id = ? AND taken_at = ?`, snapshot.ID, takenAt); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`INSERT INTO snapshots (wishlist_id, taken_at, data) VALUES (?, ?, ?)`,
+		snapshot.ID, takenAt, string(data))
+	if err != nil {
+		return err
+	}
+	snapshotID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for position, item := range snapshot.Items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO snapshot_items (snapshot_id, item_id, position, data) VALUES (?, ?, ?, ?)`,
+			snapshotID, item.ID, position, string(data)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Latest returns the most recent snapshot of the wishlist.
+func (s *SQLiteStore) Latest(wishlistID string) (*amazon.Snapshot, error) {
+	return s.readSnapshot(`SELECT id, data FROM snapshots WHERE wishlist_id = ?
+		ORDER BY taken_at DESC LIMIT 1`, wishlistID)
+}
+
+// At returns the most recent snapshot of the wishlist taken at or before t.
+func (s *SQLiteStore) At(wishlistID string, t time.Time) (*amazon.Snapshot, error) {
+	return s.readSnapshot(`SELECT id, data FROM snapshots WHERE wishlist_id = ? AND taken_at <= ?
+		ORDER BY taken_at DESC LIMIT 1`, wishlistID, t.UnixNano())
+}
+
+// Snapshots returns when each snapshot of the wishlist was taken.
+func (s *SQLiteStore) Snapshots(wishlistID string) ([]time.Time, error) {
+	if err := validateWishlistID(wishlistID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT taken_at FROM snapshots WHERE wishlist_id = ?
+		ORDER BY taken_at`, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	times := []time.Time{}
+	for rows.Next() {
+		var takenAt int64
+		if err := rows.Scan(&takenAt); err != nil {
+			return nil, err
+		}
+		times = append(times, time.Unix(0, takenAt).UTC())
+	}
+	return times, rows.Err()
+}
+
+// ItemHistory returns the versions of the item in the wishlist's snapshots.
+func (s *SQLiteStore) ItemHistory(wishlistID, itemID string) ([]*ItemVersion, error) {
+	if err := validateWishlistID(wishlistID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT snapshots.taken_at, snapshot_items.data
+		FROM snapshot_items
+		INNER JOIN snapshots ON snapshots.id = snapshot_items.snapshot_id
+		WHERE snapshots.wishlist_id = ? AND snapshot_items.item_id = ?
+		ORDER BY snapshots.taken_at`, wishlistID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*ItemVersion{}
+	for rows.Next() {
+		var takenAt int64
+		var data string
+		if err := rows.Scan(&takenAt, &data); err != nil {
+			return nil, err
+		}
+		var item amazon.Item
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, err
+		}
+		history = append(history, &ItemVersion{TakenAt: time.Unix(0, takenAt).UTC(), Item: &item})
+	}
+	return history, rows.Err()
+}
+
+// Prune deletes the snapshots the policy does not keep, and their items.
+func (s *SQLiteStore) Prune(wishlistID string, policy PrunePolicy) (int, error) {
+	times, err := s.Snapshots(wishlistID)
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := policy.pruned(times, s.now())
+	if len(pruned) < 1 {
+		return 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, t := range pruned {
+		if err := deleteSnapshots(tx, `wishlist_id = ? AND taken_at = ?`, wishlistID, t.UnixNano()); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(pruned), nil
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) readSnapshot(query string, wishlistID string, args ...interface{}) (*amazon.Snapshot, error) {
+	if err := validateWishlistID(wishlistID); err != nil {
+		return nil, err
+	}
+
+	var id int64
+	var data string
+	row := s.db.QueryRow(query, append([]interface{}{wishlistID}, args...)...)
+	if err := row.Scan(&id, &data); err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var snapshot amazon.Snapshot
+	if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT data FROM snapshot_items WHERE snapshot_id = ?
+		ORDER BY position`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshot.Items = []*amazon.Item{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var item amazon.Item
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, err
+		}
+		snapshot.Items = append(snapshot.Items, &item)
+	}
+	return &snapshot, rows.Err()
+}
+
+// deleteSnapshots deletes the snapshots matching the condition, and their
+// items.
+func deleteSnapshots(tx *sql.Tx, condition string, args ...interface{}) error {
+	if _, err := tx.Exec(`DELETE FROM snapshot_items WHERE snapshot_id IN
+		(SELECT id FROM snapshots WHERE `+condition+`)`, args...); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM snapshots WHERE `+condition, args...)
+	return err
+}
@@ -0,0 +1,4 @@
+// Package sqlitetest tests the SQLite store against a real database. It's a
+// module of its own so that the SQLite driver, and the newer Go it needs, are
+// only required to run these tests, not to use the store package.
+package sqlitetest
@@ -0,0 +1,24 @@
+package sqlitetest
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/cheshire137/gogoamazonwish/internal/storetest"
+	"github.com/cheshire137/gogoamazonwish/pkg/store"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	// Each connection to ":memory:" opens a database of its own.
+	db.SetMaxOpenConns(1)
+
+	s, err := store.NewSQLiteStore(db)
+	require.NoError(t, err)
+	defer s.Close()
+
+	storetest.Run(t, s)
+}
@@ -0,0 +1,132 @@
+// Package store keeps timestamped snapshots of wishlists, so you can see what
+// was on a list in the past and how its items changed over time.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/pkg/amazon"
+)
+
+// ErrNotFound is returned when a store has no snapshot matching a query.
+var ErrNotFound = errors.New("No snapshot found")
+
+var wishlistIDRegexp = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// Store persists snapshots of wishlists. Snapshots are identified by the
+// wishlist's ID and when they were taken.
+type Store interface {
+	// Save adds the snapshot to the history of its wishlist, replacing any
+	// snapshot of that wishlist taken at the same time.
+	Save(snapshot *amazon.Snapshot) error
+
+	// Latest returns the most recent snapshot of the wishlist, or
+	// ErrNotFound.
+	Latest(wishlistID string) (*amazon.Snapshot, error)
+
+	// At returns the most recent snapshot of the wishlist taken at or before
+	// the given time, or ErrNotFound.
+	At(wishlistID string, t time.Time) (*amazon.Snapshot, error)
+
+	// Snapshots returns when each snapshot of the wishlist was taken, oldest
+	// first.
+	Snapshots(wishlistID string) ([]time.Time, error)
+
+	// ItemHistory returns the item as it was in each snapshot of the wishlist
+	// that included it, oldest first.
+	ItemHistory(wishlistID, itemID string) ([]*ItemVersion, error)
+
+	// Prune deletes the wishlist's snapshots that the policy does not keep,
+	// and returns how many were deleted.
+	Prune(wishlistID string, policy PrunePolicy) (int, error)
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// ItemVersion is an item as it was in one snapshot.
+type ItemVersion struct {
+	// TakenAt is when the snapshot the item came from was taken.
+	TakenAt time.Time
+
+	// Item is the item as it was then.
+	Item *amazon.Item
+}
+
+// PrunePolicy describes which snapshots of a wishlist to keep. A snapshot is
+// kept if any of KeepLast or KeepDaily keep it and it is not older than
+// MaxAge. The most recent snapshot is always kept. The zero policy keeps
+// everything.
+type PrunePolicy struct {
+	// KeepLast is how many of the most recent snapshots to keep.
+	KeepLast int
+
+	// KeepDaily is how many days to keep one snapshot for, the last one taken
+	// each day, counting back from the most recent day with a snapshot.
+	KeepDaily int
+
+	// MaxAge is how old a snapshot may get before it is deleted. Zero means
+	// snapshots never get too old.
+	MaxAge time.Duration
+}
+
+// pruned returns which of the given snapshot times, sorted oldest first, the
+// policy deletes.
+func (p PrunePolicy) pruned(times []time.Time, now time.Time) []time.Time {
+	if len(times) < 1 {
+		return nil
+	}
+
+	limitCount := p.KeepLast > 0 || p.KeepDaily > 0
+	keep := make([]bool, len(times))
+	days := map[string]bool{}
+	for i := len(times) - 1; i >= 0; i-- {
+		newest := i == len(times)-1
+		recent := len(times)-1-i < p.KeepLast
+
+		day := times[i].UTC().Format("2006-01-02")
+		daily := false
+		if !days[day] && len(days) < p.KeepDaily {
+			days[day] = true
+			daily = true
+		}
+
+		tooOld := p.MaxAge > 0 && now.Sub(times[i]) > p.MaxAge
+		keep[i] = newest || ((recent || daily || !limitCount) && !tooOld)
+	}
+
+	deleted := []time.Time{}
+	for i, t := range times {
+		if !keep[i] {
+			deleted = append(deleted, t)
+		}
+	}
+	return deleted
+}
+
+func validateWishlistID(id string) error {
+	if !wishlistIDRegexp.MatchString(id) {
+		return fmt.Errorf("Invalid wishlist ID '%s'", id)
+	}
+	return nil
+}
+
+func validateSnapshot(snapshot *amazon.Snapshot) error {
+	if err := validateWishlistID(snapshot.ID); err != nil {
+		return err
+	}
+	if snapshot.TakenAt.IsZero() {
+		return fmt.Errorf("Snapshot of wishlist %s has no time", snapshot.ID)
+	}
+	return nil
+}
+
+func sortTimes(times []time.Time) {
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cheshire137/gogoamazonwish/internal/amazontest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrunePolicy(t *testing.T) {
+	now := amazontest.Start.Add(10 * 24 * time.Hour)
+	times := []time.Time{
+		amazontest.Start,
+		amazontest.Start.Add(time.Hour),
+		amazontest.Start.Add(24 * time.Hour),
+		amazontest.Start.Add(25 * time.Hour),
+		amazontest.Start.Add(48 * time.Hour),
+	}
+
+	require.Empty(t, PrunePolicy{}.pruned(times, now))
+	require.Equal(t, times[:3], PrunePolicy{KeepLast: 2}.pruned(times, now))
+	require.Equal(t, []time.Time{times[0], times[2]}, PrunePolicy{KeepDaily: 3}.pruned(times, now))
+	require.Equal(t, []time.Time{times[0], times[1], times[2]},
+		PrunePolicy{KeepLast: 1, KeepDaily: 2}.pruned(times, now))
+
+	// Too old snapshots go, except the most recent one.
+	require.Equal(t, times[:4], PrunePolicy{MaxAge: 24 * time.Hour}.pruned(times, now))
+	require.Equal(t, times[:3], PrunePolicy{KeepDaily: 3, MaxAge: 9 * 24 * time.Hour}.pruned(times, now))
+}